@@ -0,0 +1,148 @@
+package tiling
+
+import "math"
+
+//An extent crosses the antimeridian when its western bound is greater than its eastern one:
+//ExtentG{MinLon: 177, MaxLon: -178} spans 5 degrees around the 180° meridian.
+
+//NormalizeLon brings the given longitude in the range [-180, 180)
+func NormalizeLon(lon float64) float64 {
+	return wrap(lon, 360)
+}
+
+//NormalizeE brings the given mercator easting in the range [-equator/2, equator/2)
+func NormalizeE(e float64) float64 {
+	return wrap(e, equator)
+}
+
+//wrap brings v in the range [-period/2, period/2)
+func wrap(v, period float64) float64 {
+	half := period / 2
+	if v >= -half && v < half {
+		return v
+	}
+	w := math.Mod(v+half, period)
+	if w < 0 {
+		w += period
+	}
+	return w - half
+}
+
+//CrossesAntimeridian tells if the extent wraps around the 180° meridian
+func (e ExtentG) CrossesAntimeridian() bool {
+	return e.MinLon > e.MaxLon
+}
+
+//Normalize gives the extent with longitudes in the range [-180, 180],
+//an extent 360 degrees wide or more covers the whole longitude range
+func (e ExtentG) Normalize() ExtentG {
+	width := e.MaxLon - e.MinLon
+	if e.CrossesAntimeridian() {
+		width += 360
+	}
+	if width >= 360 {
+		e.MinLon, e.MaxLon = -180, 180
+		return e
+	}
+	e.MinLon = NormalizeLon(e.MinLon)
+	e.MaxLon = NormalizeLon(e.MaxLon)
+	if e.MaxLon == -180 && width > 0 {
+		e.MaxLon = 180
+	}
+	return e
+}
+
+//Split gives the parts of the extent on each side of the antimeridian,
+//the extent itself if it does not cross it
+func (e ExtentG) Split() []ExtentG {
+	e = e.Normalize()
+	if !e.CrossesAntimeridian() {
+		return []ExtentG{e}
+	}
+	west, east := e, e
+	west.MaxLon = 180
+	east.MinLon = -180
+	return []ExtentG{west, east}
+}
+
+//CrossesAntimeridian tells if the extent wraps around the 180° meridian
+func (e ExtentM) CrossesAntimeridian() bool {
+	return e.West > e.East
+}
+
+//Normalize gives the extent with eastings in the range [-equator/2, equator/2],
+//an extent as wide as the equator or more covers the whole easting range
+func (e ExtentM) Normalize() ExtentM {
+	width := e.East - e.West
+	if e.CrossesAntimeridian() {
+		width += equator
+	}
+	if width >= equator {
+		e.West, e.East = -equator/2, equator/2
+		return e
+	}
+	e.West = NormalizeE(e.West)
+	e.East = NormalizeE(e.East)
+	if e.East == -equator/2 && width > 0 {
+		e.East = equator / 2
+	}
+	return e
+}
+
+//Split gives the parts of the extent on each side of the antimeridian,
+//the extent itself if it does not cross it
+func (e ExtentM) Split() []ExtentM {
+	e = e.Normalize()
+	if !e.CrossesAntimeridian() {
+		return []ExtentM{e}
+	}
+	west, east := e, e
+	west.East = equator / 2
+	east.West = -equator / 2
+	return []ExtentM{west, east}
+}
+
+//GeoToMercExt convert the given geo extent to mercator, keeping the antimeridian crossing
+func GeoToMercExt(ge ExtentG) ExtentM {
+	mercUL := GeoToMerc(ge.UL())
+	mercLR := GeoToMerc(ge.LR())
+	return NewExtentM(mercUL, mercLR)
+}
+
+//Intersections compute the intersection between two extents that may cross the antimeridian,
+//it can be made of two parts, one for each side of the antimeridian, or be empty
+func Intersections(ext1, ext2 ExtentM) []ExtentM {
+	var res []ExtentM
+	for _, p1 := range ext1.Split() {
+		for _, p2 := range ext2.Split() {
+			if ix, ok := Intersection(p1, p2); ok {
+				res = append(res, ix)
+			}
+		}
+	}
+	return res
+}
+
+//RangesOf return the tile Ranges that cover the given EPSG:3857 extent, which may cross the antimeridian:
+//one range for each side of it. Ranges are clipped to the zoom level bounds.
+func (z *ZoomLevel) RangesOf(ext ExtentM) []Range {
+	size := int(z.mxSize)
+	bounds := Range{MinX: 0, MaxX: size - 1, MinY: 0, MaxY: size - 1, ZL: z.zoom}
+	var res []Range
+	for _, part := range ext.Split() {
+		if r, ok := RangeIntersection(z.RangeOf(part), bounds); ok {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+//RangesCardinality return the number of tiles of the Ranges that cover the given EPSG:3857 extent,
+//which may cross the antimeridian
+func (z *ZoomLevel) RangesCardinality(ext ExtentM) int64 {
+	var card int64
+	for _, r := range z.RangesOf(ext) {
+		card += r.Cardinality()
+	}
+	return card
+}
@@ -0,0 +1,136 @@
+package tiling_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestNormalizeLon(t *testing.T) {
+	lons := []float64{0, 179.5, 180, 190, -180, -190, 540, -725}
+	expected := []float64{0, 179.5, -180, -170, -180, 170, -180, -5}
+	for i, l := range lons {
+		t.Run(fmt.Sprintf("Lon %f", l), func(t *testing.T) {
+			if n := tiling.NormalizeLon(l); math.Abs(n-expected[i]) > 0.0000001 {
+				t.Errorf("Normalized lon is different (expected, actual) %f != %f", expected[i], n)
+			}
+		})
+	}
+}
+
+func TestExtentGNormalizeSplit(t *testing.T) {
+	exts := []tiling.ExtentG{
+		tiling.ExtentG{MinLat: -21, MaxLat: -12, MinLon: 177, MaxLon: -178},
+		tiling.ExtentG{MinLat: -21, MaxLat: -12, MinLon: 177, MaxLon: 182},
+		tiling.ExtentG{MinLat: 40, MaxLat: 50, MinLon: 5, MaxLon: 15},
+		tiling.ExtentG{MinLat: 40, MaxLat: 50, MinLon: -200, MaxLon: 200},
+		tiling.ExtentG{MinLat: 40, MaxLat: 50, MinLon: 170, MaxLon: 180},
+	}
+	parts := [][]tiling.ExtentG{
+		{
+			tiling.ExtentG{MinLat: -21, MaxLat: -12, MinLon: 177, MaxLon: 180},
+			tiling.ExtentG{MinLat: -21, MaxLat: -12, MinLon: -180, MaxLon: -178},
+		},
+		{
+			tiling.ExtentG{MinLat: -21, MaxLat: -12, MinLon: 177, MaxLon: 180},
+			tiling.ExtentG{MinLat: -21, MaxLat: -12, MinLon: -180, MaxLon: -178},
+		},
+		{tiling.ExtentG{MinLat: 40, MaxLat: 50, MinLon: 5, MaxLon: 15}},
+		{tiling.ExtentG{MinLat: 40, MaxLat: 50, MinLon: -180, MaxLon: 180}},
+		{tiling.ExtentG{MinLat: 40, MaxLat: 50, MinLon: 170, MaxLon: 180}},
+	}
+	for i, e := range exts {
+		t.Run(fmt.Sprintf("Extent %d %+v", i, e), func(t *testing.T) {
+			ps := e.Split()
+			if len(ps) != len(parts[i]) {
+				t.Fatalf("Got %d parts instead of %d: %+v", len(ps), len(parts[i]), ps)
+			}
+			for j, p := range ps {
+				if math.Abs(p.MinLon-parts[i][j].MinLon) > 0.0000001 || math.Abs(p.MaxLon-parts[i][j].MaxLon) > 0.0000001 ||
+					p.MinLat != parts[i][j].MinLat || p.MaxLat != parts[i][j].MaxLat {
+					t.Errorf("Part %d is different (expected, actual) %+v != %+v", j, parts[i][j], p)
+				}
+			}
+		})
+	}
+}
+
+func TestIntersections(t *testing.T) {
+	half := 20037508.342789244
+	exts := map[string]tiling.ExtentM{
+		"cross":   tiling.ExtentM{West: half - 100, East: -half + 50, South: 0, North: 100},
+		"west":    tiling.ExtentM{West: half - 500, East: half - 20, South: 50, North: 200},
+		"both":    tiling.ExtentM{West: half - 50, East: -half + 20, South: -50, North: 50},
+		"world":   tiling.ExtentM{West: -half, East: half, South: -half, North: half},
+		"outside": tiling.ExtentM{West: 0, East: 100, South: 0, North: 100},
+	}
+	tests := [][]string{
+		{"cross", "west"},
+		{"cross", "both"},
+		{"cross", "world"},
+		{"cross", "outside"},
+	}
+	expected := [][]tiling.ExtentM{
+		{tiling.ExtentM{West: half - 100, East: half - 20, South: 50, North: 100}},
+		{
+			tiling.ExtentM{West: half - 50, East: half, South: 0, North: 50},
+			tiling.ExtentM{West: -half, East: -half + 20, South: 0, North: 50},
+		},
+		{
+			tiling.ExtentM{West: half - 100, East: half, South: 0, North: 100},
+			tiling.ExtentM{West: -half, East: -half + 50, South: 0, North: 100},
+		},
+		nil,
+	}
+	for i, e := range tests {
+		t.Run(fmt.Sprintf("Intersecting %s with %s", e[0], e[1]), func(t *testing.T) {
+			xs := tiling.Intersections(exts[e[0]], exts[e[1]])
+			if len(xs) != len(expected[i]) {
+				t.Fatalf("Got %d parts instead of %d: %+v", len(xs), len(expected[i]), xs)
+			}
+			for j, x := range xs {
+				if !tiling.Equals(x, expected[i][j]) {
+					t.Errorf("Part %d is different (expected, actual) %+v != %+v", j, expected[i][j], x)
+				}
+			}
+		})
+	}
+}
+
+func TestRangesOf(t *testing.T) {
+	fiji := tiling.ExtentG{MinLat: -21, MaxLat: -12, MinLon: 177, MaxLon: -178}
+	exts := []tiling.ExtentM{
+		tiling.GeoToMercExt(fiji),
+		tiling.ExtentM{West: 626173, South: 5009378, East: 1252343, North: 5635548},
+		tiling.ExtentM{West: -20037508.342789244, South: -20037508.342789244, East: 20037508.342789244, North: 20037508.342789244},
+	}
+	zooms := []int{6, 6, 2}
+	ranges := [][]tiling.Range{
+		{
+			tiling.Range{MinX: 63, MaxX: 63, MinY: 34, MaxY: 35, ZL: 6},
+			tiling.Range{MinX: 0, MaxX: 0, MinY: 34, MaxY: 35, ZL: 6},
+		},
+		{tiling.Range{MinX: 33, MaxX: 33, MinY: 23, MaxY: 23, ZL: 6}},
+		{tiling.Range{MinX: 0, MaxX: 3, MinY: 0, MaxY: 3, ZL: 2}},
+	}
+	cards := []int64{4, 1, 16}
+	for i, e := range exts {
+		t.Run(fmt.Sprintf("Extent %d %+v", i, e), func(t *testing.T) {
+			zl := tiling.NewZoomLevel(zooms[i])
+			rs := zl.RangesOf(e)
+			if len(rs) != len(ranges[i]) {
+				t.Fatalf("Got %d ranges instead of %d: %+v", len(rs), len(ranges[i]), rs)
+			}
+			for j, r := range rs {
+				if r != ranges[i][j] {
+					t.Errorf("Range %d is different (expected, actual) %+v != %+v", j, ranges[i][j], r)
+				}
+			}
+			if c := zl.RangesCardinality(e); c != cards[i] {
+				t.Errorf("Cardinality is different (expected, actual) %d != %d", cards[i], c)
+			}
+		})
+	}
+}
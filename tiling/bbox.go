@@ -0,0 +1,331 @@
+package tiling
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//BBoxFormat is a textual form of a bounding box
+type BBoxFormat int
+
+const (
+	//BBoxCSV is "minX,minY,maxX,maxY": "minLon,minLat,maxLon,maxLat" for geo extents
+	BBoxCSV BBoxFormat = iota
+	//BBoxWKT is a WKT POLYGON with the corners of the extent, or a MULTIPOLYGON with the parts on the two sides
+	//of the antimeridian when the extent crosses it
+	BBoxWKT
+	//BBoxGeoJSON is a GeoJSON bbox array [minX, minY, maxX, maxY]
+	BBoxGeoJSON
+	//BBoxOGC is the OGC BBOX parameter with the CRS suffix: "minLat,minLon,maxLat,maxLon,urn:ogc:def:crs:EPSG::4326"
+	//for geo extents, in the axis order of the CRS, and "west,south,east,north,EPSG:3857" for mercator ones
+	BBoxOGC
+)
+
+//geoURN is the CRS of the geo OGC bounding boxes, whose axis order is latitude first
+const geoURN = "urn:ogc:def:crs:EPSG::4326"
+
+//bbox is a parsed bounding box with x eastward and y northward
+type bbox struct {
+	minX, minY, maxX, maxY float64
+	//crs is "geo", "merc" or empty when the string does not declare it
+	crs string
+}
+
+//ParseBBoxG reads a geo extent from any of the forms of BBoxFormat; a bounding box in a mercator CRS is converted.
+//A western bound greater than the eastern one crosses the antimeridian.
+func ParseBBoxG(s string) (ExtentG, error) {
+	b, err := parseBBox(s)
+	if err != nil {
+		return ExtentG{}, err
+	}
+	return b.geo(s)
+}
+
+//geo gives the geo extent of the bounding box read from s
+func (b bbox) geo(s string) (ExtentG, error) {
+	if b.crs == "merc" {
+		return MercToGeoExt(ExtentM{West: b.minX, South: b.minY, East: b.maxX, North: b.maxY}), nil
+	}
+	if math.Abs(b.minY) > 90 || math.Abs(b.maxY) > 90 {
+		return ExtentG{}, fmt.Errorf("Invalid bbox %q: latitude out of [-90, 90]", s)
+	}
+	if math.Abs(b.minX) > 180 || math.Abs(b.maxX) > 180 {
+		return ExtentG{}, fmt.Errorf("Invalid bbox %q: longitude out of [-180, 180]", s)
+	}
+	return ExtentG{MinLon: b.minX, MinLat: b.minY, MaxLon: b.maxX, MaxLat: b.maxY}, nil
+}
+
+//ParseBBoxM reads a mercator extent from any of the forms of BBoxFormat; a bounding box in a geo CRS is converted,
+//clamping the latitudes to the tiling limits. A western bound greater than the eastern one crosses the antimeridian.
+func ParseBBoxM(s string) (ExtentM, error) {
+	b, err := parseBBox(s)
+	if err != nil {
+		return ExtentM{}, err
+	}
+	if b.crs == "geo" {
+		ge, err := b.geo(s)
+		if err != nil {
+			return ExtentM{}, err
+		}
+		ge.MinLat = math.Max(tileMinLat, ge.MinLat)
+		ge.MaxLat = math.Min(tileMaxLat, ge.MaxLat)
+		return GeoToMercExt(ge), nil
+	}
+	if math.Abs(b.minX) > equator/2 || math.Abs(b.maxX) > equator/2 {
+		return ExtentM{}, fmt.Errorf("Invalid bbox %q: easting out of the mercator bounds", s)
+	}
+	if math.Abs(b.minY) > equator/2 || math.Abs(b.maxY) > equator/2 {
+		return ExtentM{}, fmt.Errorf("Invalid bbox %q: northing out of the mercator bounds", s)
+	}
+	return ExtentM{West: b.minX, South: b.minY, East: b.maxX, North: b.maxY}, nil
+}
+
+func parseBBox(s string) (bbox, error) {
+	t := strings.TrimSpace(s)
+	var b bbox
+	var err error
+	switch upper := strings.ToUpper(t); {
+	case strings.HasPrefix(t, "["):
+		b, err = parseGeoJSONBBox(t)
+	case strings.HasPrefix(upper, "POLYGON"), strings.HasPrefix(upper, "MULTIPOLYGON"), strings.HasPrefix(upper, "SRID="):
+		b, err = parseWKTBBox(t)
+	default:
+		b, err = parseCSVBBox(t)
+	}
+	if err != nil {
+		return bbox{}, fmt.Errorf("Invalid bbox %q: %v", s, err)
+	}
+	if b.minY > b.maxY {
+		return bbox{}, fmt.Errorf("Invalid bbox %q: minimum %v greater than maximum %v", s, b.minY, b.maxY)
+	}
+	return b, nil
+}
+
+//parseCSVBBox reads "minX,minY,maxX,maxY" with an optional CRS suffix swapping the axes when latitude comes first
+func parseCSVBBox(s string) (bbox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 && len(parts) != 5 {
+		return bbox{}, fmt.Errorf("expected 4 numbers and an optional CRS, got %d values", len(parts))
+	}
+	vs, err := parseNumbers(parts[:4])
+	if err != nil {
+		return bbox{}, err
+	}
+	b := bbox{minX: vs[0], minY: vs[1], maxX: vs[2], maxY: vs[3]}
+	if len(parts) == 5 {
+		crs, latFirst, err := bboxCRS(strings.TrimSpace(parts[4]))
+		if err != nil {
+			return bbox{}, err
+		}
+		b.crs = crs
+		if latFirst {
+			b = bbox{minX: vs[1], minY: vs[0], maxX: vs[3], maxY: vs[2], crs: crs}
+		}
+	}
+	return b, nil
+}
+
+//parseGeoJSONBBox reads a GeoJSON bbox array of 4 or, with elevations, 6 numbers
+func parseGeoJSONBBox(s string) (bbox, error) {
+	var vs []float64
+	if err := json.Unmarshal([]byte(s), &vs); err != nil {
+		return bbox{}, err
+	}
+	switch len(vs) {
+	case 4:
+		return bbox{minX: vs[0], minY: vs[1], maxX: vs[2], maxY: vs[3]}, nil
+	case 6:
+		return bbox{minX: vs[0], minY: vs[1], maxX: vs[3], maxY: vs[4]}, nil
+	}
+	return bbox{}, fmt.Errorf("expected 4 or 6 numbers, got %d", len(vs))
+}
+
+//parseWKTBBox reads the envelope of a WKT POLYGON or MULTIPOLYGON, optionally preceded by the EWKT SRID.
+//A MULTIPOLYGON of two parts touching the opposite sides of the antimeridian gives a crossing bounding box.
+func parseWKTBBox(s string) (bbox, error) {
+	var crs string
+	if strings.HasPrefix(strings.ToUpper(s), "SRID=") {
+		i := strings.Index(s, ";")
+		if i < 0 {
+			return bbox{}, fmt.Errorf("missing ';' after SRID")
+		}
+		c, _, err := bboxCRS("EPSG:" + strings.TrimSpace(s[len("SRID="):i]))
+		if err != nil {
+			return bbox{}, err
+		}
+		crs = c
+		s = strings.TrimSpace(s[i+1:])
+	}
+	if !strings.HasPrefix(strings.ToUpper(s), "MULTIPOLYGON") {
+		b, err := polygonEnvelope(strings.TrimSpace(s[len("POLYGON"):]))
+		b.crs = crs
+		return b, err
+	}
+	polys, err := wktParts(s[len("MULTIPOLYGON"):])
+	if err != nil {
+		return bbox{}, fmt.Errorf("expected MULTIPOLYGON(((x y, ...)), ...): %v", err)
+	}
+	var parts []bbox
+	for _, p := range polys {
+		b, err := polygonEnvelope(p)
+		if err != nil {
+			return bbox{}, err
+		}
+		parts = append(parts, b)
+	}
+	if len(parts) == 2 {
+		if parts[0].minX > parts[1].minX {
+			parts[0], parts[1] = parts[1], parts[0]
+		}
+		west, east := parts[1], parts[0]
+		for _, limit := range []float64{180, equator / 2} {
+			if east.minX == -limit && west.maxX == limit && east.maxX < west.minX {
+				return bbox{minX: west.minX, minY: math.Min(west.minY, east.minY), maxX: east.maxX,
+					maxY: math.Max(west.maxY, east.maxY), crs: crs}, nil
+			}
+		}
+	}
+	res := parts[0]
+	for _, b := range parts[1:] {
+		res.minX, res.maxX = math.Min(res.minX, b.minX), math.Max(res.maxX, b.maxX)
+		res.minY, res.maxY = math.Min(res.minY, b.minY), math.Max(res.maxY, b.maxY)
+	}
+	res.crs = crs
+	return res, nil
+}
+
+//polygonEnvelope reads the envelope of the rings of a WKT polygon body "((x y, ...), ...)"
+func polygonEnvelope(body string) (bbox, error) {
+	rings, err := wktParts(body)
+	if err != nil {
+		return bbox{}, fmt.Errorf("expected POLYGON((x y, ...)): %v", err)
+	}
+	b := bbox{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1)}
+	for _, ring := range rings {
+		positions, err := wktParts(ring)
+		if err != nil {
+			return bbox{}, fmt.Errorf("expected POLYGON((x y, ...)): %v", err)
+		}
+		for _, pos := range positions {
+			xy, err := parseNumbers(strings.Fields(pos))
+			if err != nil {
+				return bbox{}, err
+			}
+			if len(xy) < 2 {
+				return bbox{}, fmt.Errorf("invalid position %q", pos)
+			}
+			b.minX, b.maxX = math.Min(b.minX, xy[0]), math.Max(b.maxX, xy[0])
+			b.minY, b.maxY = math.Min(b.minY, xy[1]), math.Max(b.maxY, xy[1])
+		}
+	}
+	return b, nil
+}
+
+//wktParts splits the WKT list "(a, b, ...)" in its trimmed elements, at the commas outside nested parentheses
+func wktParts(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return nil, fmt.Errorf("missing parentheses around %q", s)
+	}
+	var parts []string
+	depth, start := 0, 1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 && i < len(s)-1 {
+				return nil, fmt.Errorf("unbalanced parentheses in %q", s)
+			}
+		case ',':
+			if depth == 1 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in %q", s)
+	}
+	return append(parts, strings.TrimSpace(s[start:len(s)-1])), nil
+}
+
+func parseNumbers(parts []string) ([]float64, error) {
+	vs := make([]float64, len(parts))
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid number %q", p)
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+//bboxCRS tells if the CRS is geographic ("geo") or WebMercator ("merc") and if its first axis is the latitude.
+//EPSG:4326 written as URN or URI is latitude first, the plain EPSG:4326 code keeps the traditional longitude first.
+func bboxCRS(crs string) (string, bool, error) {
+	lower := strings.ToLower(crs)
+	if isGeographicCRS(crs) || strings.HasSuffix(lower, "crs:84") {
+		latFirst := strings.HasSuffix(crs, "4326") && (strings.HasPrefix(lower, "urn:") || strings.HasPrefix(lower, "http"))
+		return "geo", latFirst, nil
+	}
+	code := crs[strings.LastIndexAny(crs, ":/")+1:]
+	switch code {
+	case EPSG, EPSG_OLD, EPSG_OLD_TYPO, "900913":
+		return "merc", false, nil
+	}
+	return "", false, fmt.Errorf("unsupported CRS %q", crs)
+}
+
+//FormatBBoxG writes the geo extent in the given form
+func FormatBBoxG(e ExtentG, f BBoxFormat) string {
+	switch f {
+	case BBoxOGC:
+		return joinNumbers(e.MinLat, e.MinLon, e.MaxLat, e.MaxLon) + "," + geoURN
+	default:
+		return formatBBox(e.MinLon, e.MinLat, e.MaxLon, e.MaxLat, f, "", 180)
+	}
+}
+
+//FormatBBoxM writes the mercator extent in the given form
+func FormatBBoxM(e ExtentM, f BBoxFormat) string {
+	return formatBBox(e.West, e.South, e.East, e.North, f, "EPSG:"+EPSG, equator/2)
+}
+
+//formatBBox writes the bounding box, limit is the easting of the antimeridian in its CRS
+func formatBBox(minX, minY, maxX, maxY float64, f BBoxFormat, crs string, limit float64) string {
+	switch f {
+	case BBoxWKT:
+		if minX > maxX {
+			return "MULTIPOLYGON((" + wktRing(minX, minY, limit, maxY) + "), (" + wktRing(-limit, minY, maxX, maxY) + "))"
+		}
+		return "POLYGON(" + wktRing(minX, minY, maxX, maxY) + ")"
+	case BBoxGeoJSON:
+		return "[" + joinNumbers(minX, minY, maxX, maxY) + "]"
+	case BBoxOGC:
+		return joinNumbers(minX, minY, maxX, maxY) + "," + crs
+	}
+	return joinNumbers(minX, minY, maxX, maxY)
+}
+
+func joinNumbers(vs ...float64) string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+//wktRing writes the closed ring of the corners of the bounding box as "(x y, ...)"
+func wktRing(minX, minY, maxX, maxY float64) string {
+	corners := []string{joinPosition(minX, minY), joinPosition(maxX, minY), joinPosition(maxX, maxY), joinPosition(minX, maxY), joinPosition(minX, minY)}
+	return "(" + strings.Join(corners, ", ") + ")"
+}
+
+func joinPosition(x, y float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64) + " " + strconv.FormatFloat(y, 'f', -1, 64)
+}
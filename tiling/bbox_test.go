@@ -0,0 +1,175 @@
+package tiling_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestParseBBoxG(t *testing.T) {
+	italy := tiling.ExtentG{MinLon: 6.6, MinLat: 36.6, MaxLon: 18.5, MaxLat: 47.1}
+	inputs := []string{
+		"6.6,36.6,18.5,47.1",
+		" 6.6, 36.6, 18.5, 47.1 ",
+		"[6.6, 36.6, 18.5, 47.1]",
+		"[6.6, 36.6, -10, 18.5, 47.1, 4800]",
+		"POLYGON((6.6 36.6, 18.5 36.6, 18.5 47.1, 6.6 47.1, 6.6 36.6))",
+		"polygon ((6.6 36.6,18.5 36.6,18.5 47.1,6.6 47.1,6.6 36.6),(10 40,11 40,11 41,10 40))",
+		"SRID=4326;POLYGON((6.6 36.6, 18.5 36.6, 18.5 47.1, 6.6 47.1))",
+		"6.6,36.6,18.5,47.1,EPSG:4326",
+		"6.6,36.6,18.5,47.1,urn:ogc:def:crs:OGC:1.3:CRS84",
+		"6.6,36.6,18.5,47.1,CRS:84",
+		"36.6,6.6,47.1,18.5,urn:ogc:def:crs:EPSG::4326",
+		"36.6,6.6,47.1,18.5,http://www.opengis.net/def/crs/EPSG/0/4326",
+	}
+	for _, in := range inputs {
+		t.Run(in, func(t *testing.T) {
+			e, err := tiling.ParseBBoxG(in)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if e != italy {
+				t.Errorf("Extent is different (expected, actual) %+v != %+v", italy, e)
+			}
+		})
+	}
+	merc := tiling.GeoToMercExt(italy)
+	for _, code := range []string{tiling.EPSG, tiling.EPSG_OLD, tiling.EPSG_OLD_TYPO} {
+		e, err := tiling.ParseBBoxG(tiling.FormatBBoxM(merc, tiling.BBoxCSV) + ",EPSG:" + code)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if math.Abs(e.MinLon-italy.MinLon) > 1e-9 || math.Abs(e.MaxLat-italy.MaxLat) > 1e-9 {
+			t.Errorf("Mercator bbox with code %s is different (expected, actual) %+v != %+v", code, italy, e)
+		}
+	}
+	fiji, err := tiling.ParseBBoxG("177,-19,-178,-16")
+	if err != nil || !fiji.CrossesAntimeridian() {
+		t.Errorf("Antimeridian bbox is %+v, %v", fiji, err)
+	}
+}
+
+func TestParseBBoxErrors(t *testing.T) {
+	inputs := []string{
+		"",
+		"1,2,3",
+		"1,2,3,4,5,6",
+		"1,2,a,4",
+		"1,50,3,40",
+		"1,2,3,4,EPSG:2154",
+		"-200,0,10,10",
+		"0,-95,10,10",
+		"[1,2,3]",
+		"[1,2,3,",
+		"POLYGON(1 2, 3 4)",
+		"POLYGON((1 2, 3))",
+		"SRID=4326POLYGON((1 2, 3 4))",
+		"MULTIPOLYGON((1 2, 3 4))",
+		"1,2,3,NaN",
+	}
+	for _, in := range inputs {
+		if e, err := tiling.ParseBBoxG(in); err == nil {
+			t.Errorf("Bbox %q should fail, got %+v", in, e)
+		}
+	}
+	for _, in := range []string{"-30000000,0,10,10", "0,0,1,1e9"} {
+		if e, err := tiling.ParseBBoxM(in); err == nil {
+			t.Errorf("Bbox %q out of the mercator bounds should fail, got %+v", in, e)
+		}
+	}
+}
+
+func TestParseBBoxM(t *testing.T) {
+	ext := tiling.ExtentM{West: 734698.5, South: 4383204.9, East: 2059410.6, North: 5955574.9}
+	inputs := []string{
+		"734698.5,4383204.9,2059410.6,5955574.9",
+		"734698.5,4383204.9,2059410.6,5955574.9,EPSG:3857",
+		"734698.5,4383204.9,2059410.6,5955574.9,urn:ogc:def:crs:EPSG::3857",
+		"[734698.5,4383204.9,2059410.6,5955574.9]",
+		"SRID=900913;POLYGON((734698.5 4383204.9, 2059410.6 5955574.9))",
+	}
+	for _, in := range inputs {
+		e, err := tiling.ParseBBoxM(in)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if e != ext {
+			t.Errorf("Extent of %q is different (expected, actual) %+v != %+v", in, ext, e)
+		}
+	}
+	world, err := tiling.ParseBBoxM("-90,-180,90,180,urn:ogc:def:crs:EPSG::4326")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(world.North-20037508.34) > 100 || math.Abs(world.West+20037508.34) > 1e-2 {
+		t.Errorf("Geo bbox should be clamped to the tiling limits: %+v", world)
+	}
+}
+
+func TestFormatBBox(t *testing.T) {
+	e := tiling.ExtentG{MinLon: 6.6, MinLat: 36.6, MaxLon: 18.5, MaxLat: 47.1}
+	formats := []tiling.BBoxFormat{tiling.BBoxCSV, tiling.BBoxWKT, tiling.BBoxGeoJSON, tiling.BBoxOGC}
+	expected := []string{
+		"6.6,36.6,18.5,47.1",
+		"POLYGON((6.6 36.6, 18.5 36.6, 18.5 47.1, 6.6 47.1, 6.6 36.6))",
+		"[6.6,36.6,18.5,47.1]",
+		"36.6,6.6,47.1,18.5,urn:ogc:def:crs:EPSG::4326",
+	}
+	for i, f := range formats {
+		s := tiling.FormatBBoxG(e, f)
+		if s != expected[i] {
+			t.Errorf("Format %d is different (expected, actual) %s != %s", f, expected[i], s)
+		}
+		if back, err := tiling.ParseBBoxG(s); err != nil || back != e {
+			t.Errorf("Round trip of %s gives %+v, %v", s, back, err)
+		}
+	}
+	m := tiling.ExtentM{West: -1000.5, South: 2000, East: 3000, North: 4000.25}
+	if s := tiling.FormatBBoxM(m, tiling.BBoxOGC); s != "-1000.5,2000,3000,4000.25,EPSG:3857" {
+		t.Errorf("Unexpected OGC mercator bbox %s", s)
+	}
+	for _, f := range formats {
+		if back, err := tiling.ParseBBoxM(tiling.FormatBBoxM(m, f)); err != nil || back != m {
+			t.Errorf("Round trip of format %d gives %+v, %v", f, back, err)
+		}
+	}
+}
+
+func TestBBoxWKTAntimeridian(t *testing.T) {
+	e := tiling.ExtentG{MinLon: 170, MinLat: -10, MaxLon: -170, MaxLat: 10}
+	s := tiling.FormatBBoxG(e, tiling.BBoxWKT)
+	expected := "MULTIPOLYGON(((170 -10, 180 -10, 180 10, 170 10, 170 -10)), ((-180 -10, -170 -10, -170 10, -180 10, -180 -10)))"
+	if s != expected {
+		t.Errorf("WKT is different (expected, actual) %s != %s", expected, s)
+	}
+	if back, err := tiling.ParseBBoxG(s); err != nil || back != e {
+		t.Errorf("Round trip of %s gives %+v, %v", s, back, err)
+	}
+	m := tiling.ExtentM{West: 19000000, South: -1000, East: -19000000, North: 1000}
+	if back, err := tiling.ParseBBoxM(tiling.FormatBBoxM(m, tiling.BBoxWKT)); err != nil || back != m {
+		t.Errorf("Round trip of the crossing mercator extent gives %+v, %v", back, err)
+	}
+	for _, v := range []string{
+		"MULTIPOLYGON(((170 -10, 180 -10, 180 10, 170 10, 170 -10) ), ((-180 -10, -170 -10, -170 10, -180 10, -180 -10)))",
+		"MULTIPOLYGON (((170 -10, 180 -10, 180 10, 170 10, 170 -10)),\n((-180 -10, -170 -10, -170 10, -180 10, -180 -10)))",
+		"MULTIPOLYGON( ( (170 -10, 180 -10, 180 10, 170 10, 170 -10) ) ,( ( -180 -10, -170 -10, -170 10, -180 10, -180 -10 ) ) )",
+	} {
+		if back, err := tiling.ParseBBoxG(v); err != nil || back != e {
+			t.Errorf("Parsing %q gives %+v, %v", v, back, err)
+		}
+	}
+	for _, v := range []string{
+		"MULTIPOLYGON(((0 0, 1 1)), ((5 5, 6 6))",
+		"MULTIPOLYGON(((0 0, 1 1))), ((5 5, 6 6))",
+		"MULTIPOLYGON(((0 0, 1 1)) ((5 5, 6 6)))",
+	} {
+		if _, err := tiling.ParseBBoxG(v); err == nil {
+			t.Errorf("Expected an error parsing %q", v)
+		}
+	}
+	union, err := tiling.ParseBBoxG("MULTIPOLYGON(((0 0, 1 1)), ((5 5, 6 6)))")
+	if err != nil || union != (tiling.ExtentG{MinLon: 0, MinLat: 0, MaxLon: 6, MaxLat: 6}) {
+		t.Errorf("Envelope of the multipolygon is different: %+v %v", union, err)
+	}
+}
@@ -0,0 +1,186 @@
+package tiling
+
+import (
+	"fmt"
+	"sort"
+)
+
+//maxCurveZoom is the deepest zoom level whose tile ids fit in 64 bits
+const maxCurveZoom = 31
+
+//IDInterval is an inclusive interval of tile ids
+type IDInterval struct {
+	Min uint64
+	Max uint64
+}
+
+//zoomBase gives the number of tiles of all the zoom levels coarser than z,
+//it is also correct for z = 32 as the shift wraps to 0
+func zoomBase(z int) uint64 {
+	return ((uint64(1) << uint(2*z)) - 1) / 3
+}
+
+//checkCurveTile verifies that the tile can be indexed on a curve
+func checkCurveTile(t Tile) error {
+	if t.Z < 0 || t.Z > maxCurveZoom {
+		return fmt.Errorf("Zoom %d out of range [0, %d]", t.Z, maxCurveZoom)
+	}
+	n := 1 << uint(t.Z)
+	if t.X < 0 || t.Y < 0 || t.X >= n || t.Y >= n {
+		return fmt.Errorf("Tile (x, y, z)(%d, %d, %d) out of the zoom level", t.X, t.Y, t.Z)
+	}
+	return nil
+}
+
+//hilbertRotate rotates the quadrant of side n as the Hilbert curve requires
+func hilbertRotate(n uint64, x, y uint64, rx, ry uint64) (uint64, uint64) {
+	if ry == 0 {
+		if rx != 0 {
+			x = n - 1 - x
+			y = n - 1 - y
+		}
+		return y, x
+	}
+	return x, y
+}
+
+//hilbertPos gives the position of (x, y) along the Hilbert curve of a grid with side n
+func hilbertPos(n, x, y uint64) uint64 {
+	var d uint64
+	for s := n / 2; s > 0; s /= 2 {
+		var rx, ry uint64
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		d += s * s * ((3 * rx) ^ ry)
+		x, y = hilbertRotate(s, x, y, rx, ry)
+	}
+	return d
+}
+
+//mortonPos gives the position of (x, y) along the Z-order curve: bits of x in even positions, of y in odd ones
+func mortonPos(x, y uint64) uint64 {
+	var d uint64
+	for i := uint(0); i < 32; i++ {
+		d |= (x>>i&1)<<(2*i) | (y>>i&1)<<(2*i+1)
+	}
+	return d
+}
+
+//HilbertID gives the position of the tile along the Hilbert curve of its zoom level,
+//counted after all the tiles of the coarser zoom levels. It is the tile id of PMTiles v3.
+func (t Tile) HilbertID() (uint64, error) {
+	if err := checkCurveTile(t); err != nil {
+		return 0, err
+	}
+	return zoomBase(t.Z) + hilbertPos(uint64(1)<<uint(t.Z), uint64(t.X), uint64(t.Y)), nil
+}
+
+//MortonID gives the position of the tile along the Z-order curve of its zoom level,
+//counted after all the tiles of the coarser zoom levels. Inside a zoom level it follows the quadkey order.
+func (t Tile) MortonID() (uint64, error) {
+	if err := checkCurveTile(t); err != nil {
+		return 0, err
+	}
+	return zoomBase(t.Z) + mortonPos(uint64(t.X), uint64(t.Y)), nil
+}
+
+//zoomOfID splits a tile id in its zoom level and the position inside it
+func zoomOfID(id uint64) (int, uint64, error) {
+	for z := 0; z <= maxCurveZoom; z++ {
+		if id < zoomBase(z+1) {
+			return z, id - zoomBase(z), nil
+		}
+	}
+	return 0, 0, fmt.Errorf("Tile id %d out of range", id)
+}
+
+//TileOfHilbertID gives the tile with the given Hilbert id
+func TileOfHilbertID(id uint64) (Tile, error) {
+	z, pos, err := zoomOfID(id)
+	if err != nil {
+		return Tile{}, err
+	}
+	n := uint64(1) << uint(z)
+	var x, y uint64
+	for s := uint64(1); s < n; s *= 2 {
+		rx := 1 & (pos / 2)
+		ry := 1 & (pos ^ rx)
+		x, y = hilbertRotate(s, x, y, rx, ry)
+		x += s * rx
+		y += s * ry
+		pos /= 4
+	}
+	return Tile{X: int(x), Y: int(y), Z: z}, nil
+}
+
+//TileOfMortonID gives the tile with the given Morton id
+func TileOfMortonID(id uint64) (Tile, error) {
+	z, pos, err := zoomOfID(id)
+	if err != nil {
+		return Tile{}, err
+	}
+	var x, y uint64
+	for i := uint(0); i < 32; i++ {
+		x |= (pos >> (2 * i) & 1) << i
+		y |= (pos >> (2*i + 1) & 1) << i
+	}
+	return Tile{X: int(x), Y: int(y), Z: z}, nil
+}
+
+//HilbertIntervals gives the minimal sorted list of contiguous Hilbert id intervals covering the range
+func (r Range) HilbertIntervals() ([]IDInterval, error) {
+	return r.intervals(func(n, x, y uint64) uint64 { return hilbertPos(n, x, y) })
+}
+
+//MortonIntervals gives the minimal sorted list of contiguous Morton id intervals covering the range
+func (r Range) MortonIntervals() ([]IDInterval, error) {
+	return r.intervals(func(n, x, y uint64) uint64 { return mortonPos(x, y) })
+}
+
+//intervals decomposes the range in aligned quadrants, whose ids are contiguous on both curves,
+//and merges the adjacent ones
+func (r Range) intervals(pos func(n, x, y uint64) uint64) ([]IDInterval, error) {
+	if r.Empty() {
+		return nil, nil
+	}
+	for _, t := range []Tile{{X: r.MinX, Y: r.MinY, Z: r.ZL}, {X: r.MaxX, Y: r.MaxY, Z: r.ZL}} {
+		if err := checkCurveTile(t); err != nil {
+			return nil, err
+		}
+	}
+	base := zoomBase(r.ZL)
+	var res []IDInterval
+	var visit func(level int, qx, qy int)
+	visit = func(level int, qx, qy int) {
+		side := 1 << uint(r.ZL-level)
+		q := Range{MinX: qx * side, MaxX: (qx+1)*side - 1, MinY: qy * side, MaxY: (qy+1)*side - 1, ZL: r.ZL}
+		ix, ok := RangeIntersection(q, r)
+		if !ok {
+			return
+		}
+		if ix == q {
+			cells := uint64(side) * uint64(side)
+			start := base + pos(uint64(1)<<uint(level), uint64(qx), uint64(qy))*cells
+			res = append(res, IDInterval{Min: start, Max: start + cells - 1})
+			return
+		}
+		for _, c := range (Tile{X: qx, Y: qy, Z: level}).Children() {
+			visit(c.Z, c.X, c.Y)
+		}
+	}
+	visit(0, 0, 0)
+	sort.Slice(res, func(i, j int) bool { return res[i].Min < res[j].Min })
+	merged := res[:0]
+	for _, iv := range res {
+		if n := len(merged); n > 0 && merged[n-1].Max+1 == iv.Min {
+			merged[n-1].Max = iv.Max
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged, nil
+}
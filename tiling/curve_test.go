@@ -0,0 +1,151 @@
+package tiling_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestHilbertID(t *testing.T) {
+	for z := 0; z <= 4; z++ {
+		base := (uint64(1)<<uint(2*z) - 1) / 3
+		var prev tiling.Tile
+		for pos := uint64(0); pos < 1<<uint(2*z); pos++ {
+			tl, err := tiling.TileOfHilbertID(base + pos)
+			if err != nil || tl.Z != z {
+				t.Fatalf("Id %d gives the tile %+v at zoom %d %v", base+pos, tl, z, err)
+			}
+			if id, err := tl.HilbertID(); err != nil || id != base+pos {
+				t.Errorf("Tile id is different (expected, actual) %d != %d %v", base+pos, id, err)
+			}
+			if dx, dy := tl.X-prev.X, tl.Y-prev.Y; pos > 0 && dx*dx+dy*dy != 1 {
+				t.Errorf("Tiles %+v and %+v are consecutive on the curve but not adjacent", prev, tl)
+			}
+			prev = tl
+		}
+	}
+	//the curve of every zoom level ends in the upper right tile, the last one of zoom 31 has the greatest id
+	last := tiling.Tile{X: 2147483647, Y: 0, Z: 31}
+	if id, err := last.HilbertID(); err != nil || id != 6148914691236517204 {
+		t.Errorf("Tile id is different (expected, actual) %d != %d %v", uint64(6148914691236517204), id, err)
+	}
+	if _, err := tiling.TileOfHilbertID(6148914691236517205); err == nil {
+		t.Errorf("Id beyond zoom 31 should fail")
+	}
+	invalid := []tiling.Tile{
+		tiling.Tile{X: 2, Y: 0, Z: 1},
+		tiling.Tile{X: 0, Y: -1, Z: 3},
+		tiling.Tile{X: 0, Y: 0, Z: 32},
+	}
+	for _, tl := range invalid {
+		if _, err := tl.HilbertID(); err == nil {
+			t.Errorf("Tile %+v should fail", tl)
+		}
+	}
+}
+
+func TestMortonID(t *testing.T) {
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 1, Y: 0, Z: 1},
+		tiling.Tile{X: 0, Y: 1, Z: 1},
+		tiling.Tile{X: 3, Y: 5, Z: 3},
+		tiling.Tile{X: 2147483647, Y: 2147483647, Z: 31},
+	}
+	ids := []uint64{0, 2, 3, 21 + 39, 6148914691236517204}
+	for i, tl := range tiles {
+		t.Run(fmt.Sprintf("Tile %d (X,Y,Z)(%d, %d, %d)", i, tl.X, tl.Y, tl.Z), func(t *testing.T) {
+			id, err := tl.MortonID()
+			if err != nil || id != ids[i] {
+				t.Errorf("Morton id is different (expected, actual) %d != %d %v", ids[i], id, err)
+			}
+			back, err := tiling.TileOfMortonID(id)
+			if err != nil || back != tl {
+				t.Errorf("Tile is different (expected, actual) %+v != %+v %v", tl, back, err)
+			}
+		})
+	}
+	if _, err := tiling.TileOfMortonID(18446744073709551615); err == nil {
+		t.Errorf("Id beyond zoom 31 should fail")
+	}
+}
+
+func TestMortonQuadkeyOrder(t *testing.T) {
+	r := tiling.Range{MinX: 0, MaxX: 7, MinY: 0, MaxY: 7, ZL: 3}
+	byID := make(map[uint64]tiling.Tile)
+	r.Each(func(tl tiling.Tile) bool {
+		id, _ := tl.MortonID()
+		byID[id] = tl
+		return true
+	})
+	var prev string
+	for id := uint64(21); id < 21+64; id++ {
+		qk := byID[id].Quadkey()
+		if qk <= prev {
+			t.Errorf("Morton order does not follow quadkey order: %q after %q", qk, prev)
+		}
+		prev = qk
+	}
+}
+
+func TestRangeIntervals(t *testing.T) {
+	ranges := []tiling.Range{
+		tiling.Range{MinX: 0, MaxX: 7, MinY: 0, MaxY: 7, ZL: 3},
+		tiling.Range{MinX: 0, MaxX: 1, MinY: 0, MaxY: 1, ZL: 3},
+		tiling.Range{MinX: 1, MaxX: 5, MinY: 2, MaxY: 6, ZL: 3},
+		tiling.Range{MinX: 100, MaxX: 180, MinY: 300, MaxY: 333, ZL: 10},
+	}
+	kinds := []string{"hilbert", "morton"}
+	for i, r := range ranges {
+		for _, kind := range kinds {
+			t.Run(fmt.Sprintf("Range %d %s", i, kind), func(t *testing.T) {
+				var ivs []tiling.IDInterval
+				var err error
+				id := func(tl tiling.Tile) uint64 {
+					v, _ := tl.HilbertID()
+					return v
+				}
+				if kind == "morton" {
+					ivs, err = r.MortonIntervals()
+					id = func(tl tiling.Tile) uint64 {
+						v, _ := tl.MortonID()
+						return v
+					}
+				} else {
+					ivs, err = r.HilbertIntervals()
+				}
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				in := make(map[uint64]bool)
+				r.Each(func(tl tiling.Tile) bool {
+					in[id(tl)] = true
+					return true
+				})
+				var total uint64
+				for j, iv := range ivs {
+					if j > 0 && iv.Min <= ivs[j-1].Max+1 {
+						t.Errorf("Intervals %+v and %+v are not sorted or not merged", ivs[j-1], iv)
+					}
+					for v := iv.Min; v <= iv.Max; v++ {
+						if !in[v] {
+							t.Fatalf("Id %d of interval %+v is outside the range", v, iv)
+						}
+					}
+					total += iv.Max - iv.Min + 1
+				}
+				if total != uint64(r.Cardinality()) {
+					t.Errorf("Intervals cover %d ids instead of %d", total, r.Cardinality())
+				}
+			})
+		}
+	}
+	full, _ := ranges[0].HilbertIntervals()
+	if len(full) != 1 || full[0] != (tiling.IDInterval{Min: 21, Max: 84}) {
+		t.Errorf("Full zoom level should be a single interval: %+v", full)
+	}
+	if _, err := (tiling.Range{MinX: 0, MaxX: 8, MinY: 0, MaxY: 0, ZL: 3}).HilbertIntervals(); err == nil {
+		t.Errorf("Range out of the zoom level should fail")
+	}
+}
@@ -0,0 +1,45 @@
+package tiling
+
+import "math"
+
+const (
+	wgs84Flattening    = 1 / 298.257223563
+	wgs84Eccentricity2 = wgs84Flattening * (2 - wgs84Flattening)
+	//inverseIterations bounds the iterations of the inverse ellipsoidal Mercator
+	inverseIterations = 15
+)
+
+var wgs84Eccentricity = math.Sqrt(wgs84Eccentricity2)
+
+//WorldMercator is the Mercator projection on the WGS84 ellipsoid (EPSG:3395)
+type WorldMercator struct{}
+
+//Forward projects the given geo point
+func (WorldMercator) Forward(g PointG) PointM {
+	phi := g.Lat * deg2rad
+	esin := wgs84Eccentricity * math.Sin(phi)
+	north := wgs84SphericalAxis * math.Log(math.Tan(math.Pi/4+phi/2)*math.Pow((1-esin)/(1+esin), wgs84Eccentricity/2))
+	east := wgs84SphericalAxis * g.Lon * deg2rad
+	return PointM{N: north, E: east}
+}
+
+//Inverse gives the geo point of the given projected point
+func (WorldMercator) Inverse(m PointM) PointG {
+	t := math.Exp(-m.N / wgs84SphericalAxis)
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < inverseIterations; i++ {
+		esin := wgs84Eccentricity * math.Sin(phi)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-esin)/(1+esin), wgs84Eccentricity/2))
+		if math.Abs(next-phi) < 1e-12 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	return PointG{Lat: phi * rad2deg, Lon: m.E / wgs84SphericalAxis * rad2deg}
+}
+
+//EPSG returns the code of World Mercator
+func (WorldMercator) EPSG() string {
+	return "3395"
+}
@@ -0,0 +1,59 @@
+package tiling_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestWorldMercator(t *testing.T) {
+	geos := []tiling.PointG{
+		tiling.PointG{Lon: 9.1682557, Lat: 45.4498397},
+		tiling.PointG{Lon: 115.8741812, Lat: -31.8973283},
+		tiling.PointG{Lon: 25.7686782, Lat: 71.1673627},
+		tiling.PointG{Lon: 0, Lat: 0},
+	}
+	mercs := []tiling.PointM{
+		tiling.PointM{N: 5662157.266290, E: 1020605.555987},
+		tiling.PointM{N: -3727265.434670, E: 12899054.847272},
+		tiling.PointM{N: 11419248.689471, E: 2868556.135640},
+		tiling.PointM{N: 0, E: 0},
+	}
+	var p tiling.Projection = tiling.WorldMercator{}
+	if p.EPSG() != "3395" {
+		t.Errorf("Wrong EPSG code %s", p.EPSG())
+	}
+	for i, g := range geos {
+		t.Run(fmt.Sprintf("Point %d (Lat,Lon)(%f, %f)", i, g.Lat, g.Lon), func(t *testing.T) {
+			m := p.Forward(g)
+			if math.Abs(m.N-mercs[i].N) > 0.001 || math.Abs(m.E-mercs[i].E) > 0.001 {
+				t.Errorf("Projected point is different (expected, actual) %+v != %+v", mercs[i], m)
+			}
+			b := p.Inverse(m)
+			if math.Abs(b.Lat-g.Lat) > 0.0000001 || math.Abs(b.Lon-g.Lon) > 0.0000001 {
+				t.Errorf("Inverse point is different (expected, actual) %+v != %+v", g, b)
+			}
+		})
+	}
+}
+
+func TestProjectExtent(t *testing.T) {
+	projections := []tiling.Projection{tiling.WebMercator{}, tiling.WorldMercator{}}
+	ge := tiling.ExtentG{MinLat: 40, MaxLat: 50, MinLon: 5, MaxLon: 15}
+	for _, p := range projections {
+		t.Run(fmt.Sprintf("EPSG:%s", p.EPSG()), func(t *testing.T) {
+			me := tiling.ProjectExtent(p, ge)
+			ul := p.Forward(ge.UL())
+			lr := p.Forward(ge.LR())
+			if !tiling.Equals(me, tiling.NewExtentM(ul, lr)) {
+				t.Errorf("Extent is different (expected, actual) %+v != %+v", tiling.NewExtentM(ul, lr), me)
+			}
+			back := tiling.UnprojectExtent(p, me)
+			if math.Abs(back.MinLat-ge.MinLat) > 0.0000001 || math.Abs(back.MaxLon-ge.MaxLon) > 0.0000001 {
+				t.Errorf("Unprojected extent is different (expected, actual) %+v != %+v", ge, back)
+			}
+		})
+	}
+}
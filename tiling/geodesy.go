@@ -0,0 +1,137 @@
+package tiling
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	//EarthMeanRadius is the IUGG mean radius of the Earth in meters, used by the spherical formulas
+	EarthMeanRadius = 6371008.8
+	//earthAuthalicRadius is the radius of the sphere with the same surface of the WGS84 ellipsoid
+	earthAuthalicRadius = 6371007.181
+	wgs84SemiMinorAxis  = wgs84SphericalAxis * (1 - wgs84Flattening)
+	//vincentyIterations bounds the iterations of the Vincenty inverse formula
+	vincentyIterations = 200
+)
+
+//HaversineDistance gives the great-circle distance in meters between the given points on a sphere of EarthMeanRadius
+func HaversineDistance(a, b PointG) float64 {
+	phi1, phi2 := a.Lat*deg2rad, b.Lat*deg2rad
+	dPhi := phi2 - phi1
+	dLambda := (b.Lon - a.Lon) * deg2rad
+	h := math.Pow(math.Sin(dPhi/2), 2) + math.Cos(phi1)*math.Cos(phi2)*math.Pow(math.Sin(dLambda/2), 2)
+	return 2 * EarthMeanRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+//VincentyDistance gives the geodesic distance in meters between the given points on the WGS84 ellipsoid,
+//it fails when the formula does not converge, which happens for nearly antipodal points
+//https://en.wikipedia.org/wiki/Vincenty%27s_formulae
+func VincentyDistance(a, b PointG) (float64, error) {
+	f := wgs84Flattening
+	l := (b.Lon - a.Lon) * deg2rad
+	u1 := math.Atan((1 - f) * math.Tan(a.Lat*deg2rad))
+	u2 := math.Atan((1 - f) * math.Tan(b.Lat*deg2rad))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+	lambda := l
+	for i := 0; i < vincentyIterations; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, nil
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+		c := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+		prev := lambda
+		lambda = l + (1-c)*f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < 1e-12 {
+			uSq := cos2Alpha * (wgs84SphericalAxis*wgs84SphericalAxis - wgs84SemiMinorAxis*wgs84SemiMinorAxis) / (wgs84SemiMinorAxis * wgs84SemiMinorAxis)
+			bigA := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+			bigB := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+			deltaSigma := bigB * sinSigma * (cos2SigmaM + bigB/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+				bigB/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+			return wgs84SemiMinorAxis * bigA * (sigma - deltaSigma), nil
+		}
+	}
+	return 0, fmt.Errorf("Vincenty formula does not converge for (lat, lon)(%f, %f) and (%f, %f)", a.Lat, a.Lon, b.Lat, b.Lon)
+}
+
+//InitialBearing gives the great-circle bearing in degrees, clockwise from north in [0, 360), to follow from a to reach b
+func InitialBearing(a, b PointG) float64 {
+	phi1, phi2 := a.Lat*deg2rad, b.Lat*deg2rad
+	dLambda := (b.Lon - a.Lon) * deg2rad
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	return math.Mod(math.Atan2(y, x)*rad2deg+360, 360)
+}
+
+//Destination gives the point reached from p travelling distance meters along the great circle with the given
+//initial bearing in degrees, on a sphere of EarthMeanRadius. The longitude is normalized in [-180, 180).
+func Destination(p PointG, bearing, distance float64) PointG {
+	delta := distance / EarthMeanRadius
+	theta := bearing * deg2rad
+	phi1, lambda1 := p.Lat*deg2rad, p.Lon*deg2rad
+	sinPhi2 := math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta)
+	phi2 := math.Asin(sinPhi2)
+	y := math.Sin(theta) * math.Sin(delta) * math.Cos(phi1)
+	x := math.Cos(delta) - math.Sin(phi1)*sinPhi2
+	lambda2 := lambda1 + math.Atan2(y, x)
+	return PointG{Lat: phi2 * rad2deg, Lon: NormalizeLon(lambda2 * rad2deg)}
+}
+
+//GeodesicArea gives the area in square meters of the given extent on the WGS84 ellipsoid,
+//the extent may cross the antimeridian
+func GeodesicArea(e ExtentG) float64 {
+	dLon := e.MaxLon - e.MinLon
+	if e.CrossesAntimeridian() {
+		dLon += 360
+	}
+	zone := func(lat float64) float64 {
+		s := math.Sin(lat * deg2rad)
+		es := wgs84Eccentricity * s
+		return s/(2*(1-es*es)) + math.Log((1+es)/(1-es))/(4*wgs84Eccentricity)
+	}
+	return math.Abs(dLon*deg2rad*wgs84SemiMinorAxis*wgs84SemiMinorAxis*(zone(e.MaxLat)-zone(e.MinLat)))
+}
+
+//GeodesicPolygonArea gives the area in square meters of the given polygon, minus its holes,
+//on the sphere with the same surface of the WGS84 ellipsoid, using the Chamberlain and Duquette approximation.
+//Edges may cross the antimeridian.
+//https://trs.jpl.nasa.gov/handle/2014/40409
+func GeodesicPolygonArea(p PolygonG) float64 {
+	var area float64
+	for i, ring := range p {
+		a := math.Abs(sphericalRingArea(ring))
+		if i == 0 {
+			area += a
+		} else {
+			area -= a
+		}
+	}
+	return math.Max(0, area)
+}
+
+//sphericalRingArea gives the signed spherical area of the ring
+func sphericalRingArea(ring []PointG) float64 {
+	var sum float64
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		dLambda := NormalizeLon(b.Lon-a.Lon) * deg2rad
+		sum += dLambda * (2 + math.Sin(a.Lat*deg2rad) + math.Sin(b.Lat*deg2rad))
+	}
+	return sum * earthAuthalicRadius * earthAuthalicRadius / 2
+}
+
+//MercatorScaleFactor gives the scale factor of the Mercator projection at the given latitude:
+//distances and lengths measured on the map must be divided by it, areas by its square
+func MercatorScaleFactor(lat float64) float64 {
+	return 1 / math.Cos(lat*deg2rad)
+}
@@ -0,0 +1,109 @@
+package tiling_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+var (
+	flindersPeak = tiling.PointG{Lat: -(37 + 57/60.0 + 3.72030/3600), Lon: 144 + 25/60.0 + 29.52440/3600}
+	buninyong    = tiling.PointG{Lat: -(37 + 39/60.0 + 10.15610/3600), Lon: 143 + 55/60.0 + 35.38390/3600}
+	lax          = tiling.PointG{Lat: 33.9425, Lon: -118.408056}
+	jfk          = tiling.PointG{Lat: 40.639722, Lon: -73.778889}
+)
+
+func TestHaversineDistance(t *testing.T) {
+	pairs := [][2]tiling.PointG{{lax, jfk}, {{Lat: 0, Lon: 179.5}, {Lat: 0, Lon: -179.5}}, {jfk, jfk}}
+	dists := []float64{3974208.735, 111195.080, 0}
+	for i, p := range pairs {
+		t.Run(fmt.Sprintf("%+v", p), func(t *testing.T) {
+			if d := tiling.HaversineDistance(p[0], p[1]); math.Abs(d-dists[i]) > 1e-3 {
+				t.Errorf("Distance is different (expected, actual) %f != %f", dists[i], d)
+			}
+		})
+	}
+}
+
+func TestVincentyDistance(t *testing.T) {
+	d, err := tiling.VincentyDistance(flindersPeak, buninyong)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(d-54972.271) > 0.01 {
+		t.Errorf("Distance is different (expected, actual) %f != %f", 54972.271, d)
+	}
+	if d, _ := tiling.VincentyDistance(jfk, jfk); d != 0 {
+		t.Errorf("Distance of a point from itself is %f", d)
+	}
+	if d, _ := tiling.VincentyDistance(tiling.PointG{Lat: 0, Lon: 0}, tiling.PointG{Lat: 0, Lon: 1}); math.Abs(d-111319.491) > 0.001 {
+		t.Errorf("A degree on the equator is %f", d)
+	}
+	if _, err := tiling.VincentyDistance(tiling.PointG{Lat: 0, Lon: 0}, tiling.PointG{Lat: 0.5, Lon: 179.7}); err == nil {
+		t.Errorf("Nearly antipodal points should fail")
+	}
+}
+
+func TestBearingDestination(t *testing.T) {
+	if b := tiling.InitialBearing(flindersPeak, buninyong); math.Abs(b-306.983874) > 1e-6 {
+		t.Errorf("Bearing is different (expected, actual) %f != %f", 306.983874, b)
+	}
+	if b := tiling.InitialBearing(tiling.PointG{Lat: 10, Lon: 5}, tiling.PointG{Lat: 0, Lon: 5}); b != 180 {
+		t.Errorf("Bearing due south is %f", b)
+	}
+	dist := tiling.HaversineDistance(lax, jfk)
+	dest := tiling.Destination(lax, tiling.InitialBearing(lax, jfk), dist)
+	if math.Abs(dest.Lat-jfk.Lat) > 1e-9 || math.Abs(dest.Lon-jfk.Lon) > 1e-9 {
+		t.Errorf("Destination is different (expected, actual) %+v != %+v", jfk, dest)
+	}
+	east := tiling.Destination(tiling.PointG{Lat: 0, Lon: 179.5}, 90, 111195.08)
+	if math.Abs(east.Lat) > 1e-9 || math.Abs(east.Lon+179.5) > 1e-6 {
+		t.Errorf("Destination across the antimeridian is %+v", east)
+	}
+}
+
+func TestGeodesicArea(t *testing.T) {
+	world := tiling.ExtentG{MinLon: -180, MaxLon: 180, MinLat: -90, MaxLat: 90}
+	if a := tiling.GeodesicArea(world); math.Abs(a-5.10065621724e14) > 1e6 {
+		t.Errorf("World area is different (expected, actual) %g != %g", 5.10065621724e14, a)
+	}
+	fiji := tiling.ExtentG{MinLon: 179, MaxLon: -179, MinLat: -18, MaxLat: -16}
+	same := tiling.ExtentG{MinLon: 10, MaxLon: 12, MinLat: -18, MaxLat: -16}
+	if a, b := tiling.GeodesicArea(fiji), tiling.GeodesicArea(same); math.Abs(a-b) > 1e-3 || a < 4.7e10 || a > 4.8e10 {
+		t.Errorf("Antimeridian extent area %g differs from %g", a, b)
+	}
+	square := tiling.PolygonG{
+		{{Lat: 44, Lon: 10}, {Lat: 44, Lon: 11}, {Lat: 45, Lon: 11}, {Lat: 45, Lon: 10}, {Lat: 44, Lon: 10}},
+		{{Lat: 44.2, Lon: 10.2}, {Lat: 44.8, Lon: 10.2}, {Lat: 44.8, Lon: 10.8}, {Lat: 44.2, Lon: 10.8}},
+	}
+	outer := tiling.GeodesicPolygonArea(square[:1])
+	ext := tiling.GeodesicArea(tiling.ExtentG{MinLon: 10, MaxLon: 11, MinLat: 44, MaxLat: 45})
+	if math.Abs(outer-ext)/ext > 0.005 {
+		t.Errorf("Polygon area %g differs from the extent area %g", outer, ext)
+	}
+	hole := tiling.GeodesicPolygonArea(square[1:])
+	if a := tiling.GeodesicPolygonArea(square); math.Abs(a-(outer-hole)) > 1e-3 {
+		t.Errorf("Polygon area %g does not subtract the hole", a)
+	}
+	across := tiling.PolygonG{{{Lat: -18, Lon: 179}, {Lat: -18, Lon: -179}, {Lat: -16, Lon: -179}, {Lat: -16, Lon: 179}}}
+	within := tiling.PolygonG{{{Lat: -18, Lon: 10}, {Lat: -18, Lon: 12}, {Lat: -16, Lon: 12}, {Lat: -16, Lon: 10}}}
+	if a, b := tiling.GeodesicPolygonArea(across), tiling.GeodesicPolygonArea(within); math.Abs(a-b) > 1e-3 {
+		t.Errorf("Antimeridian polygon area %g differs from %g", a, b)
+	}
+}
+
+func TestMercatorScaleFactor(t *testing.T) {
+	lats := []float64{0, 60, -60, 85}
+	factors := []float64{1, 2, 2, 11.473713}
+	for i, lat := range lats {
+		if k := tiling.MercatorScaleFactor(lat); math.Abs(k-factors[i]) > 1e-6 {
+			t.Errorf("Scale factor at %f is different (expected, actual) %f != %f", lat, factors[i], k)
+		}
+	}
+	z := tiling.NewZoomLevel(10)
+	if r := z.Resolution() / tiling.MercatorScaleFactor(60); math.Abs(r-z.GroundResolution(60)) > 1e-9 {
+		t.Errorf("Corrected resolution %f differs from the ground resolution %f", r, z.GroundResolution(60))
+	}
+}
@@ -0,0 +1,321 @@
+package tiling
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+//geoJSONObject is the subset of a GeoJSON object (RFC 7946) used by the package
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates,omitempty"`
+	Geometry    *geoJSONObject  `json:"geometry,omitempty"`
+	Geometries  []geoJSONObject `json:"geometries,omitempty"`
+	Features    []geoJSONObject `json:"features,omitempty"`
+	Properties  interface{}     `json:"properties,omitempty"`
+}
+
+//ParseGeoJSONPolygons extracts the polygons of a GeoJSON document: a Polygon or MultiPolygon geometry,
+//a GeometryCollection, a Feature or a FeatureCollection. Other geometry types are ignored.
+func ParseGeoJSONPolygons(data []byte) ([]PolygonG, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("Invalid GeoJSON: %v", err)
+	}
+	return obj.polygons()
+}
+
+func (o *geoJSONObject) polygons() ([]PolygonG, error) {
+	switch o.Type {
+	case "FeatureCollection":
+		var res []PolygonG
+		for i := range o.Features {
+			ps, err := o.Features[i].polygons()
+			if err != nil {
+				return nil, fmt.Errorf("Feature %d: %v", i, err)
+			}
+			res = append(res, ps...)
+		}
+		return res, nil
+	case "Feature":
+		if o.Geometry == nil {
+			return nil, nil
+		}
+		return o.Geometry.polygons()
+	case "GeometryCollection":
+		var res []PolygonG
+		for i := range o.Geometries {
+			ps, err := o.Geometries[i].polygons()
+			if err != nil {
+				return nil, fmt.Errorf("Geometry %d: %v", i, err)
+			}
+			res = append(res, ps...)
+		}
+		return res, nil
+	case "Polygon":
+		var coords [][][]float64
+		if err := json.Unmarshal(o.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("Invalid Polygon coordinates: %v", err)
+		}
+		p, err := polygonOfCoords(coords)
+		if err != nil {
+			return nil, err
+		}
+		return []PolygonG{p}, nil
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := json.Unmarshal(o.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("Invalid MultiPolygon coordinates: %v", err)
+		}
+		res := make([]PolygonG, 0, len(coords))
+		for _, c := range coords {
+			p, err := polygonOfCoords(c)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, p)
+		}
+		return res, nil
+	case "Point", "MultiPoint", "LineString", "MultiLineString":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("Unknown GeoJSON type %q", o.Type)
+	}
+}
+
+//polygonOfCoords builds a polygon from GeoJSON [lon, lat] positions
+func polygonOfCoords(coords [][][]float64) (PolygonG, error) {
+	p := make(PolygonG, 0, len(coords))
+	for i, ring := range coords {
+		if len(ring) < 3 {
+			return nil, fmt.Errorf("Ring %d has %d positions, at least 3 are needed", i, len(ring))
+		}
+		r := make([]PointG, len(ring))
+		for j, pos := range ring {
+			if len(pos) < 2 {
+				return nil, fmt.Errorf("Position %d of ring %d has %d coordinates", j, i, len(pos))
+			}
+			r[j] = PointG{Lon: pos[0], Lat: pos[1]}
+		}
+		p = append(p, r)
+	}
+	return p, nil
+}
+
+//TileGeoJSON gives a GeoJSON FeatureCollection with the WGS84 polygon of the XYZ tile, see TilesGeoJSON
+func TileGeoJSON(t Tile) ([]byte, error) {
+	return TilesGeoJSON([]Tile{t}, false)
+}
+
+//RangeGeoJSON gives a GeoJSON FeatureCollection with the WGS84 polygons of the tiles of the XYZ range, see TilesGeoJSON
+func RangeGeoJSON(r Range, merge bool) ([]byte, error) {
+	ts := make([]Tile, 0, r.Cardinality())
+	r.Each(func(t Tile) bool {
+		ts = append(ts, t)
+		return true
+	})
+	return TilesGeoJSON(ts, merge)
+}
+
+//TilesGeoJSON gives a GeoJSON FeatureCollection with a WGS84 Polygon feature for each of the given XYZ tiles,
+//with z, x, y and quadkey properties. With merge the adjacent tiles of each zoom level are merged instead
+//in a MultiPolygon feature with the outline of the tiles, with z and count properties; repeated tiles count once.
+func TilesGeoJSON(ts []Tile, merge bool) ([]byte, error) {
+	for _, t := range ts {
+		size := 1 << uint(t.Z)
+		if t.Z < 0 || t.Z >= maxQuadkeyZoom || t.X < 0 || t.Y < 0 || t.X >= size || t.Y >= size {
+			return nil, fmt.Errorf("Tile out of zoom level bounds: (x, y, z)(%d, %d, %d)", t.X, t.Y, t.Z)
+		}
+	}
+	fc := struct {
+		Type     string          `json:"type"`
+		Features []geoJSONObject `json:"features"`
+	}{Type: "FeatureCollection", Features: []geoJSONObject{}}
+	if merge {
+		byZoom := make(map[int][]Tile)
+		seen := make(map[Tile]bool, len(ts))
+		var zooms []int
+		for _, t := range ts {
+			if seen[t] {
+				continue
+			}
+			seen[t] = true
+			if _, ok := byZoom[t.Z]; !ok {
+				zooms = append(zooms, t.Z)
+			}
+			byZoom[t.Z] = append(byZoom[t.Z], t)
+		}
+		sort.Ints(zooms)
+		for _, z := range zooms {
+			f, err := geoJSONFeature("MultiPolygon", outline(z, byZoom[z]), map[string]interface{}{"z": z, "count": len(byZoom[z])})
+			if err != nil {
+				return nil, err
+			}
+			fc.Features = append(fc.Features, f)
+		}
+	} else {
+		for _, t := range ts {
+			ge := MercToGeoExt(ExtentOf(t))
+			ring := [][]float64{{ge.MinLon, ge.MinLat}, {ge.MaxLon, ge.MinLat}, {ge.MaxLon, ge.MaxLat}, {ge.MinLon, ge.MaxLat}, {ge.MinLon, ge.MinLat}}
+			props := map[string]interface{}{"z": t.Z, "x": t.X, "y": t.Y, "quadkey": t.Quadkey()}
+			f, err := geoJSONFeature("Polygon", [][][]float64{ring}, props)
+			if err != nil {
+				return nil, err
+			}
+			fc.Features = append(fc.Features, f)
+		}
+	}
+	return json.Marshal(fc)
+}
+
+func geoJSONFeature(geomType string, coords interface{}, props map[string]interface{}) (geoJSONObject, error) {
+	raw, err := json.Marshal(coords)
+	if err != nil {
+		return geoJSONObject{}, err
+	}
+	return geoJSONObject{Type: "Feature", Geometry: &geoJSONObject{Type: geomType, Coordinates: raw}, Properties: props}, nil
+}
+
+//gridVertex is a corner of the tiles of a zoom level, Y grows southward as the tile rows
+type gridVertex struct {
+	X, Y int
+}
+
+//outline gives the MultiPolygon coordinates of the union of the tiles of zoom level z, with counterclockwise
+//outer rings and clockwise holes. The boundary edges of the tiles are chained after removing the shared ones.
+func outline(z int, ts []Tile) [][][][]float64 {
+	edges := make(map[[2]gridVertex]bool)
+	for _, t := range ts {
+		ll, lr := gridVertex{t.X, t.Y + 1}, gridVertex{t.X + 1, t.Y + 1}
+		ur, ul := gridVertex{t.X + 1, t.Y}, gridVertex{t.X, t.Y}
+		//counterclockwise on the map: the tile lies on the left of every edge
+		for _, e := range [][2]gridVertex{{ll, lr}, {lr, ur}, {ur, ul}, {ul, ll}} {
+			if edges[e] {
+				continue
+			}
+			if rev := [2]gridVertex{e[1], e[0]}; edges[rev] {
+				delete(edges, rev)
+				continue
+			}
+			edges[e] = true
+		}
+	}
+	next := make(map[gridVertex][]gridVertex)
+	for e := range edges {
+		next[e[0]] = append(next[e[0]], e[1])
+	}
+	starts := make([]gridVertex, 0, len(next))
+	for v, outs := range next {
+		starts = append(starts, v)
+		sortVertices(outs)
+	}
+	sortVertices(starts)
+	var outers, holes [][]gridVertex
+	for _, start := range starts {
+		for len(next[start]) > 0 {
+			ring := []gridVertex{start}
+			prev, cur := start, start
+			for {
+				cur = popLeftmost(next, prev, cur, len(ring) == 1)
+				if cur == start {
+					break
+				}
+				ring = append(ring, cur)
+				prev = ring[len(ring)-2]
+			}
+			if gridRingArea(ring) > 0 {
+				outers = append(outers, ring)
+			} else {
+				holes = append(holes, ring)
+			}
+		}
+	}
+	polys := make([][][]gridVertex, len(outers))
+	for i, o := range outers {
+		polys[i] = [][]gridVertex{o}
+	}
+	for _, h := range holes {
+		best := -1
+		for i, o := range outers {
+			if gridRingContains(o, h[0]) && (best < 0 || gridRingArea(o) < gridRingArea(outers[best])) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			polys[best] = append(polys[best], h)
+		}
+	}
+	zl := NewZoomLevel(z)
+	res := make([][][][]float64, len(polys))
+	for i, p := range polys {
+		for _, ring := range p {
+			coords := make([][]float64, 0, len(ring)+1)
+			for _, v := range append(ring, ring[0]) {
+				g := MercToGeo(PointM{E: float64(v.X)*zl.hLength - equator/2, N: meridian - float64(v.Y)*zl.vLength})
+				coords = append(coords, []float64{g.Lon, g.Lat})
+			}
+			res[i] = append(res[i], coords)
+		}
+	}
+	return res
+}
+
+//sortVertices sorts the vertices by row and column
+func sortVertices(vs []gridVertex) {
+	sort.Slice(vs, func(i, j int) bool {
+		if vs[i].Y != vs[j].Y {
+			return vs[i].Y < vs[j].Y
+		}
+		return vs[i].X < vs[j].X
+	})
+}
+
+//popLeftmost removes and returns the edge leaving cur that turns most to the left coming from prev,
+//so that tiles touching only at a corner give separate rings
+func popLeftmost(next map[gridVertex][]gridVertex, prev, cur gridVertex, first bool) gridVertex {
+	outs := next[cur]
+	best := 0
+	if !first {
+		//Y grows southward, so a left turn on the map has a negative cross product
+		turn := func(v gridVertex) int {
+			cross := (cur.X-prev.X)*(v.Y-cur.Y) - (cur.Y-prev.Y)*(v.X-cur.X)
+			return -cross
+		}
+		for i := 1; i < len(outs); i++ {
+			if turn(outs[i]) > turn(outs[best]) {
+				best = i
+			}
+		}
+	}
+	v := outs[best]
+	next[cur] = append(outs[:best], outs[best+1:]...)
+	return v
+}
+
+//gridRingArea gives twice the signed area of the ring, positive when counterclockwise on the map
+func gridRingArea(ring []gridVertex) int64 {
+	var a int64
+	for i, v := range ring {
+		w := ring[(i+1)%len(ring)]
+		a += int64(w.X)*int64(v.Y) - int64(v.X)*int64(w.Y)
+	}
+	return a
+}
+
+//gridRingContains tells if the vertex v, which is not on the ring, is inside it
+func gridRingContains(ring []gridVertex, v gridVertex) bool {
+	//test the center of the tile at the lower right of v, which lies inside the hole starting at v
+	px, py := float64(v.X)+0.5, float64(v.Y)+0.5
+	in := false
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		if (float64(a.Y) > py) != (float64(b.Y) > py) {
+			x := float64(a.X) + (py-float64(a.Y))*float64(b.X-a.X)/float64(b.Y-a.Y)
+			if px < x {
+				in = !in
+			}
+		}
+	}
+	return in
+}
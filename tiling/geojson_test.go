@@ -0,0 +1,212 @@
+package tiling_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestParseGeoJSONPolygons(t *testing.T) {
+	docs := []string{
+		`{"type":"Polygon","coordinates":[[[9,45],[10,45],[10,46],[9,45]]]}`,
+		`{"type":"MultiPolygon","coordinates":[[[[9,45],[10,45],[10,46],[9,45]]],[[[1,2],[3,2],[3,4],[1,2]],[[1.5,2.5],[2,2.5],[2,3],[1.5,2.5]]]]}`,
+		`{"type":"FeatureCollection","features":[
+			{"type":"Feature","properties":{"name":"a"},"geometry":{"type":"Polygon","coordinates":[[[9,45],[10,45],[10,46],[9,45]]]}},
+			{"type":"Feature","properties":{},"geometry":{"type":"LineString","coordinates":[[9,45],[10,45]]}},
+			{"type":"Feature","properties":{},"geometry":null},
+			{"type":"Feature","geometry":{"type":"GeometryCollection","geometries":[{"type":"Polygon","coordinates":[[[1,2],[3,2],[3,4],[1,2]]]}]}}
+		]}`,
+	}
+	counts := [][]int{
+		{1},
+		{1, 2},
+		{1, 1},
+	}
+	for i, d := range docs {
+		t.Run(fmt.Sprintf("Document %d", i), func(t *testing.T) {
+			ps, err := tiling.ParseGeoJSONPolygons([]byte(d))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(ps) != len(counts[i]) {
+				t.Fatalf("Got %d polygons instead of %d", len(ps), len(counts[i]))
+			}
+			for j, p := range ps {
+				if len(p) != counts[i][j] {
+					t.Errorf("Polygon %d has %d rings instead of %d", j, len(p), counts[i][j])
+				}
+			}
+			if ps[0][0][1] != (tiling.PointG{Lon: 10, Lat: 45}) && ps[0][0][1] != (tiling.PointG{Lon: 3, Lat: 2}) {
+				t.Errorf("Coordinates are not read as [lon, lat]: %+v", ps[0][0][1])
+			}
+		})
+	}
+}
+
+func TestParseGeoJSONPolygonsErrors(t *testing.T) {
+	docs := []string{
+		`{"type":"Polygon","coordinates":[[[9,45],[10,45]]]}`,
+		`{"type":"Polygon","coordinates":[[[9],[10,45],[10,46]]]}`,
+		`{"type":"Circle","coordinates":[9,45]}`,
+		`{"type":"Polygon","coordinates":"nope"}`,
+		`not json`,
+	}
+	for i, d := range docs {
+		t.Run(fmt.Sprintf("Document %d", i), func(t *testing.T) {
+			if ps, err := tiling.ParseGeoJSONPolygons([]byte(d)); err == nil {
+				t.Errorf("Expected an error, got %+v", ps)
+			}
+		})
+	}
+}
+
+func TestGeoToMercPolygon(t *testing.T) {
+	ps, err := tiling.ParseGeoJSONPolygons([]byte(`{"type":"Polygon","coordinates":[[[-10,-90],[10,-90],[10,90],[-10,90]]]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pm := tiling.GeoToMercPolygon(ps[0])
+	zl := tiling.NewZoomLevel(3)
+	ts := zl.PolygonTiles([]tiling.PolygonM{pm}, tiling.Intersecting)
+	if len(ts) != 16 {
+		t.Errorf("Got %d tiles instead of 16: %+v", len(ts), ts)
+	}
+}
+
+type exportedCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Type     string `json:"type"`
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	} `json:"features"`
+}
+
+func decodeCollection(t *testing.T, data []byte, err error) exportedCollection {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var fc exportedCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatalf("Invalid GeoJSON: %v", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		t.Fatalf("Unexpected type %s", fc.Type)
+	}
+	return fc
+}
+
+//lonLatArea gives twice the signed area of the ring in degrees, positive when counterclockwise
+func lonLatArea(ring []tiling.PointG) float64 {
+	var a float64
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		a += p.Lon*q.Lat - q.Lon*p.Lat
+	}
+	return a
+}
+
+func TestTileGeoJSON(t *testing.T) {
+	tl := tiling.Tile{X: 1098, Y: 766, Z: 11}
+	data, err := tiling.TileGeoJSON(tl)
+	fc := decodeCollection(t, data, err)
+	if len(fc.Features) != 1 || fc.Features[0].Geometry.Type != "Polygon" {
+		t.Fatalf("Unexpected features %+v", fc.Features)
+	}
+	props := fc.Features[0].Properties
+	if props["z"] != 11.0 || props["x"] != 1098.0 || props["y"] != 766.0 || props["quadkey"] != tl.Quadkey() {
+		t.Errorf("Unexpected properties %+v", props)
+	}
+	polys, err := tiling.ParseGeoJSONPolygons(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ge := tiling.MercToGeoExt(tiling.ExtentOf(tl))
+	ring := polys[0][0]
+	if len(ring) != 5 || ring[0] != ring[4] || lonLatArea(ring) <= 0 {
+		t.Fatalf("Expected a closed counterclockwise ring, got %v", ring)
+	}
+	for _, p := range ring {
+		if (p.Lon != ge.MinLon && p.Lon != ge.MaxLon) || (p.Lat != ge.MinLat && p.Lat != ge.MaxLat) {
+			t.Errorf("Vertex %+v is not a corner of the tile extent %+v", p, ge)
+		}
+	}
+	if _, err := tiling.TileGeoJSON(tiling.Tile{X: 4, Y: 0, Z: 2}); err == nil {
+		t.Errorf("A tile out of the zoom level should fail")
+	}
+}
+
+func TestRangeGeoJSON(t *testing.T) {
+	r := tiling.Range{MinX: 10, MaxX: 12, MinY: 20, MaxY: 21, ZL: 6}
+	data, err := tiling.RangeGeoJSON(r, false)
+	fc := decodeCollection(t, data, err)
+	if int64(len(fc.Features)) != r.Cardinality() {
+		t.Errorf("Expected %d features, got %d", r.Cardinality(), len(fc.Features))
+	}
+	data, err = tiling.RangeGeoJSON(r, true)
+	fc = decodeCollection(t, data, err)
+	if len(fc.Features) != 1 || fc.Features[0].Geometry.Type != "MultiPolygon" || fc.Features[0].Properties["count"] != 6.0 {
+		t.Fatalf("Unexpected merged features %+v", fc.Features)
+	}
+	polys, _ := tiling.ParseGeoJSONPolygons(data)
+	ge := tiling.MercToGeoExt(tiling.NewExtentM(tiling.ExtentOf(tiling.Tile{X: 10, Y: 20, Z: 6}).UL(), tiling.ExtentOf(tiling.Tile{X: 12, Y: 21, Z: 6}).LR()))
+	if len(polys) != 1 || len(polys[0]) != 1 {
+		t.Fatalf("Expected a single outline, got %v", polys)
+	}
+	for _, p := range polys[0][0] {
+		if p.Lon < ge.MinLon-1e-9 || p.Lon > ge.MaxLon+1e-9 || p.Lat < ge.MinLat-1e-9 || p.Lat > ge.MaxLat+1e-9 {
+			t.Errorf("Vertex %+v is outside the range extent %+v", p, ge)
+		}
+	}
+}
+
+func TestTilesGeoJSONMerge(t *testing.T) {
+	ring := rangeTiles(4, 6, 4, 6, 5, map[tiling.Tile]bool{{X: 5, Y: 5, Z: 5}: true})
+	diagonal := []tiling.Tile{{X: 0, Y: 0, Z: 3}, {X: 1, Y: 1, Z: 3}}
+	tiles := append(append(ring, diagonal...), tiling.Tile{X: 2, Y: 2, Z: 3}, tiling.Tile{X: 9, Y: 9, Z: 5})
+	data, err := tiling.TilesGeoJSON(tiles, true)
+	fc := decodeCollection(t, data, err)
+	if len(fc.Features) != 2 || fc.Features[0].Properties["z"] != 3.0 || fc.Features[1].Properties["z"] != 5.0 {
+		t.Fatalf("Expected a feature per zoom level, got %+v", fc.Features)
+	}
+	var z3, z5 [][][][]float64
+	json.Unmarshal(fc.Features[0].Geometry.Coordinates, &z3)
+	json.Unmarshal(fc.Features[1].Geometry.Coordinates, &z5)
+	if len(z3) != 3 {
+		t.Errorf("Tiles touching at a corner should give separate polygons, got %d", len(z3))
+	}
+	if len(z5) != 2 || len(z5[0]) != 2 || len(z5[1]) != 1 {
+		t.Fatalf("Expected a polygon with a hole and a square, got %v", z5)
+	}
+	polys, _ := tiling.ParseGeoJSONPolygons(data)
+	for _, p := range polys {
+		for i, r := range p {
+			if a := lonLatArea(r); (i == 0) != (a > 0) {
+				t.Errorf("Ring %d has the wrong winding: %f", i, a)
+			}
+		}
+	}
+	again, _ := tiling.TilesGeoJSON(tiles, true)
+	if string(again) != string(data) {
+		t.Errorf("Merged GeoJSON is not deterministic")
+	}
+}
+
+func TestTilesGeoJSONMergeDuplicates(t *testing.T) {
+	tiles := []tiling.Tile{{X: 0, Y: 0, Z: 2}, {X: 1, Y: 0, Z: 2}, {X: 0, Y: 0, Z: 2}}
+	data, err := tiling.TilesGeoJSON(tiles, true)
+	fc := decodeCollection(t, data, err)
+	if len(fc.Features) != 1 || fc.Features[0].Properties["count"] != 2.0 {
+		t.Fatalf("Repeated tiles should count once, got %+v", fc.Features)
+	}
+	unique, _ := tiling.TilesGeoJSON(tiles[:2], true)
+	if string(unique) != string(data) {
+		t.Errorf("Repeated tiles change the outline (expected, actual) %s != %s", unique, data)
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/trealtamira/gopkgs/tiling
 
 go 1.14
+
+require github.com/mattn/go-sqlite3 v1.14.6
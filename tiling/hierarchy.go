@@ -0,0 +1,85 @@
+package tiling
+
+import "fmt"
+
+//Ancestor gives the tile at the coarser zoom level z that contains the current tile
+func (t Tile) Ancestor(z int) (Tile, error) {
+	if z < 0 || z > t.Z {
+		return Tile{}, fmt.Errorf("Zoom %d is not an ancestor level of tile (x, y, z)(%d, %d, %d)", z, t.X, t.Y, t.Z)
+	}
+	shift := uint(t.Z - z)
+	return Tile{X: t.X >> shift, Y: t.Y >> shift, Z: z}, nil
+}
+
+//Parent gives the tile at the previous zoom level that contains the current tile
+func (t Tile) Parent() (Tile, error) {
+	return t.Ancestor(t.Z - 1)
+}
+
+//Ancestors gives all the tiles containing the current one, from the parent up to zoom 0
+func (t Tile) Ancestors() []Tile {
+	if t.Z <= 0 {
+		return nil
+	}
+	res := make([]Tile, 0, t.Z)
+	for z := t.Z - 1; z >= 0; z-- {
+		a, _ := t.Ancestor(z)
+		res = append(res, a)
+	}
+	return res
+}
+
+//Children gives the four tiles of the next zoom level covering the current tile,
+//in quadkey order: upper left, upper right, lower left, lower right
+func (t Tile) Children() []Tile {
+	x, y, z := t.X*2, t.Y*2, t.Z+1
+	return []Tile{
+		Tile{X: x, Y: y, Z: z},
+		Tile{X: x + 1, Y: y, Z: z},
+		Tile{X: x, Y: y + 1, Z: z},
+		Tile{X: x + 1, Y: y + 1, Z: z},
+	}
+}
+
+//Descendants gives the tile Range covering the current tile at the finer zoom level z
+func (t Tile) Descendants(z int) (Range, error) {
+	if z < t.Z {
+		return Range{}, fmt.Errorf("Zoom %d is not a descendant level of tile (x, y, z)(%d, %d, %d)", z, t.X, t.Y, t.Z)
+	}
+	if t.Z < 0 || z >= maxQuadkeyZoom {
+		return Range{}, fmt.Errorf("Zoom %d is too deep to be represented", z)
+	}
+	side := 1 << uint(z-t.Z)
+	r := Range{
+		MinX: t.X * side,
+		MaxX: (t.X+1)*side - 1,
+		MinY: t.Y * side,
+		MaxY: (t.Y+1)*side - 1,
+		ZL:   z,
+	}
+	return r, nil
+}
+
+//Siblings gives the other three tiles sharing the same parent, zoom 0 tile has no siblings
+func (t Tile) Siblings() []Tile {
+	p, err := t.Parent()
+	if err != nil {
+		return nil
+	}
+	res := make([]Tile, 0, 3)
+	for _, c := range p.Children() {
+		if c != t {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+//Contains tells if the other tile is the current one or lies inside it at a finer zoom level
+func (t Tile) Contains(other Tile) bool {
+	a, err := other.Ancestor(t.Z)
+	if err != nil {
+		return false
+	}
+	return a == t
+}
@@ -0,0 +1,147 @@
+package tiling_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func within(inner, outer tiling.ExtentM) bool {
+	const lim = 0.0000001
+	return inner.West >= outer.West-lim && inner.East <= outer.East+lim &&
+		inner.South >= outer.South-lim && inner.North <= outer.North+lim
+}
+
+func TestAncestor(t *testing.T) {
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 106960, Y: 75432, Z: 17},
+		tiling.Tile{X: 33, Y: 23, Z: 6},
+		tiling.Tile{X: 5, Y: 8, Z: 4},
+	}
+	zooms := []int{6, 5, 0}
+	expected := []tiling.Tile{
+		tiling.Tile{X: 52, Y: 36, Z: 6},
+		tiling.Tile{X: 16, Y: 11, Z: 5},
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+	}
+	for i, tl := range tiles {
+		t.Run(fmt.Sprintf("Tile %d (X,Y,Z)(%d, %d, %d)", i, tl.X, tl.Y, tl.Z), func(t *testing.T) {
+			a, err := tl.Ancestor(zooms[i])
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if a != expected[i] {
+				t.Errorf("Ancestor is different (expected, actual) %+v != %+v", expected[i], a)
+			}
+			if !within(tiling.ExtentOf(tl), tiling.ExtentOf(a)) {
+				t.Errorf("Tile extent is not inside the ancestor extent")
+			}
+			if !a.Contains(tl) || tl.Contains(a) {
+				t.Errorf("Containment between %+v and %+v is wrong", a, tl)
+			}
+		})
+	}
+	if _, err := (tiling.Tile{X: 0, Y: 0, Z: 0}).Parent(); err == nil {
+		t.Errorf("Zoom 0 tile should not have a parent")
+	}
+	if _, err := (tiling.Tile{X: 1, Y: 1, Z: 2}).Ancestor(3); err == nil {
+		t.Errorf("Ancestor at a finer zoom should fail")
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	tl := tiling.Tile{X: 33, Y: 23, Z: 6}
+	as := tl.Ancestors()
+	if len(as) != 6 {
+		t.Fatalf("Expected 6 ancestors, got %d", len(as))
+	}
+	for i, a := range as {
+		if a.Z != tl.Z-1-i || !a.Contains(tl) {
+			t.Errorf("Wrong ancestor %d: %+v", i, a)
+		}
+	}
+}
+
+func TestChildren(t *testing.T) {
+	tl := tiling.Tile{X: 5, Y: 8, Z: 4}
+	children := tl.Children()
+	if len(children) != 4 {
+		t.Fatalf("Expected 4 children, got %d", len(children))
+	}
+	for i, c := range children {
+		t.Run(fmt.Sprintf("Child %d (X,Y,Z)(%d, %d, %d)", i, c.X, c.Y, c.Z), func(t *testing.T) {
+			p, err := c.Parent()
+			if err != nil || p != tl {
+				t.Errorf("Parent of child is %+v, %v", p, err)
+			}
+			if !within(tiling.ExtentOf(c), tiling.ExtentOf(tl)) {
+				t.Errorf("Child extent is not inside the parent extent")
+			}
+			if c.Quadkey() != fmt.Sprintf("%s%d", tl.Quadkey(), i) {
+				t.Errorf("Child %d is not in quadkey order: %s", i, c.Quadkey())
+			}
+		})
+	}
+	siblings := children[2].Siblings()
+	if len(siblings) != 3 {
+		t.Fatalf("Expected 3 siblings, got %d", len(siblings))
+	}
+	for _, s := range siblings {
+		if s == children[2] {
+			t.Errorf("Tile is listed among its own siblings")
+		}
+	}
+	if s := (tiling.Tile{}).Siblings(); len(s) != 0 {
+		t.Errorf("Zoom 0 tile should not have siblings: %+v", s)
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 5, Y: 8, Z: 4},
+		tiling.Tile{X: 33, Y: 23, Z: 6},
+	}
+	zooms := []int{2, 6, 6}
+	ranges := []tiling.Range{
+		tiling.Range{MinX: 0, MaxX: 3, MinY: 0, MaxY: 3, ZL: 2},
+		tiling.Range{MinX: 20, MaxX: 23, MinY: 32, MaxY: 35, ZL: 6},
+		tiling.Range{MinX: 33, MaxX: 33, MinY: 23, MaxY: 23, ZL: 6},
+	}
+	for i, tl := range tiles {
+		t.Run(fmt.Sprintf("Tile %d (X,Y,Z)(%d, %d, %d)", i, tl.X, tl.Y, tl.Z), func(t *testing.T) {
+			r, err := tl.Descendants(zooms[i])
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if r != ranges[i] {
+				t.Errorf("Range is different (expected, actual) %+v != %+v", ranges[i], r)
+			}
+			corner := tiling.Tile{X: r.MaxX, Y: r.MaxY, Z: r.ZL}
+			if !tl.Contains(corner) {
+				t.Errorf("Tile does not contain %+v", corner)
+			}
+			outside := tiling.Tile{X: r.MaxX + 1, Y: r.MaxY, Z: r.ZL}
+			if tl.Contains(outside) {
+				t.Errorf("Tile should not contain %+v", outside)
+			}
+		})
+	}
+	if _, err := (tiling.Tile{X: 1, Y: 1, Z: 2}).Descendants(1); err == nil {
+		t.Errorf("Descendants at a coarser zoom should fail")
+	}
+	deep := tiling.Tile{X: 1023, Y: 1023, Z: 10}
+	if r, err := deep.Descendants(70); err == nil {
+		t.Errorf("Descendants beyond the deepest zoom should fail, got %+v", r)
+	}
+	z := strconv.IntSize - 2
+	r, err := deep.Descendants(z)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if max := 1<<uint(z) - 1; r.MaxX != max || r.MaxY != max {
+		t.Errorf("Deepest range is different (expected, actual) %d != %+v", max, r)
+	}
+}
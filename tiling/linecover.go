@@ -0,0 +1,186 @@
+package tiling
+
+import (
+	"math"
+	"sort"
+)
+
+//GeoToMercLine convert the given geo polyline to mercator, latitudes are clamped to the tiling limits
+func GeoToMercLine(line []PointG) []PointM {
+	res := make([]PointM, len(line))
+	for i, g := range line {
+		g.Lat = math.Max(tileMinLat, math.Min(tileMaxLat, g.Lat))
+		res[i] = GeoToMerc(g)
+	}
+	return res
+}
+
+//LineTiles gives the tiles of the zoom level traversed by the polyline, in the order they are reached.
+//Every tile whose ground distance from the line is within buffer meters is included as well:
+//the buffer of each segment is converted to mercator meters with the scale factor of the latitude
+//of the segment farthest from the equator, so it covers at least buffer meters along the whole segment.
+//Each tile appears only once, where it is reached for the first time.
+//A line passing exactly through a tile corner also includes one of the two tiles sharing that corner.
+//Lines must not cross the antimeridian.
+func (z *ZoomLevel) LineTiles(line []PointM, buffer float64) []Tile {
+	return z.lineTiles(line, func(a, b PointM) float64 {
+		lat := math.Min(tileMaxLat, math.Max(math.Abs(MercToGeo(a).Lat), math.Abs(MercToGeo(b).Lat)))
+		return buffer / math.Cos(lat*deg2rad)
+	})
+}
+
+//LineTilesPx is LineTiles with the buffer expressed in pixels of the zoom level
+func (z *ZoomLevel) LineTilesPx(line []PointM, buffer float64) []Tile {
+	d := buffer * z.Resolution()
+	return z.lineTiles(line, func(a, b PointM) float64 {
+		return d
+	})
+}
+
+//lineTiles gives the tiles traversed by the polyline and within the mercator buffer of each segment
+func (z *ZoomLevel) lineTiles(line []PointM, buffer func(a, b PointM) float64) []Tile {
+	seen := make(map[Tile]struct{})
+	var res []Tile
+	add := func(t Tile) bool {
+		if _, ok := seen[t]; !ok {
+			seen[t] = struct{}{}
+			res = append(res, ConvertTile(t, XYZ, z.scheme))
+		}
+		return true
+	}
+	if len(line) == 1 {
+		line = []PointM{line[0], line[0]}
+	}
+	for i := 0; i+1 < len(line); i++ {
+		a, b := line[i], line[i+1]
+		d := buffer(a, b)
+		if d <= 0 {
+			z.traverse(a, b, add)
+			continue
+		}
+		for _, t := range z.segmentBuffer(a, b, d) {
+			add(t)
+		}
+	}
+	return res
+}
+
+//segmentBuffer gives the XYZ tiles within distance d from the segment ab, ordered along the segment
+func (z *ZoomLevel) segmentBuffer(a, b PointM, d float64) []Tile {
+	ext := ExtentM{
+		West:  math.Min(a.E, b.E) - d,
+		East:  math.Max(a.E, b.E) + d,
+		South: math.Min(a.N, b.N) - d,
+		North: math.Max(a.N, b.N) + d,
+	}
+	last := int(z.mxSize) - 1
+	bounds := Range{MinX: 0, MaxX: last, MinY: 0, MaxY: last, ZL: z.zoom}
+	ul, lr := z.xyzTileOfMerc(ext.UL()), z.xyzTileOfMerc(ext.LR())
+	r, ok := RangeIntersection(Range{MinX: ul.X, MaxX: lr.X, MinY: ul.Y, MaxY: lr.Y, ZL: z.zoom}, bounds)
+	if !ok {
+		return nil
+	}
+	type candidate struct {
+		t    Tile
+		pos  float64
+		dist float64
+	}
+	var cs []candidate
+	r.Each(func(t Tile) bool {
+		te := z.xyzExtentOfTile(t.X, t.Y)
+		if segmentRectDistance(a, b, te) <= d {
+			c := PointM{N: (te.North + te.South) / 2, E: (te.East + te.West) / 2}
+			cs = append(cs, candidate{t: t, pos: segmentProjection(a, b, c), dist: pointSegmentDistance(c, a, b)})
+		}
+		return true
+	})
+	sort.SliceStable(cs, func(i, j int) bool {
+		if cs[i].pos != cs[j].pos {
+			return cs[i].pos < cs[j].pos
+		}
+		return cs[i].dist < cs[j].dist
+	})
+	res := make([]Tile, len(cs))
+	for i, c := range cs {
+		res[i] = c.t
+	}
+	return res
+}
+
+//segmentProjection gives the parameter of the projection of p on the line through a and b,
+//0 is a and 1 is b
+func segmentProjection(a, b, p PointM) float64 {
+	dE, dN := b.E-a.E, b.N-a.N
+	l2 := dE*dE + dN*dN
+	if l2 == 0 {
+		return 0
+	}
+	return ((p.E-a.E)*dE + (p.N-a.N)*dN) / l2
+}
+
+//pointSegmentDistance gives the distance between p and the segment ab
+func pointSegmentDistance(p, a, b PointM) float64 {
+	t := math.Max(0, math.Min(1, segmentProjection(a, b, p)))
+	return math.Hypot(p.E-(a.E+t*(b.E-a.E)), p.N-(a.N+t*(b.N-a.N)))
+}
+
+//segmentRectDistance gives the distance between the segment ab and the extent, 0 if they intersect
+func segmentRectDistance(a, b PointM, e ExtentM) float64 {
+	if segmentIntersectsRect(a, b, e) {
+		return 0
+	}
+	d := math.Min(pointRectDistance(a, e), pointRectDistance(b, e))
+	for _, c := range []PointM{e.UL(), e.UR(), e.LR(), e.LL()} {
+		d = math.Min(d, pointSegmentDistance(c, a, b))
+	}
+	return d
+}
+
+//pointRectDistance gives the distance between p and the extent, 0 if p is inside
+func pointRectDistance(p PointM, e ExtentM) float64 {
+	dE := math.Max(0, math.Max(e.West-p.E, p.E-e.East))
+	dN := math.Max(0, math.Max(e.South-p.N, p.N-e.North))
+	return math.Hypot(dE, dN)
+}
+
+//segmentIntersectsRect tells if the segment ab touches the extent
+func segmentIntersectsRect(a, b PointM, e ExtentM) bool {
+	_, _, ok := clipSegment(a, b, e)
+	return ok
+}
+
+//clipSegment gives the part of the segment ab inside the extent, using Liang-Barsky clipping,
+//and false if the segment does not touch the extent
+func clipSegment(a, b PointM, e ExtentM) (PointM, PointM, bool) {
+	t0, t1 := 0.0, 1.0
+	dE, dN := b.E-a.E, b.N-a.N
+	clip := func(p, q float64) bool {
+		if p == 0 {
+			return q >= 0
+		}
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return false
+			}
+			t0 = math.Max(t0, r)
+		} else {
+			if r < t0 {
+				return false
+			}
+			t1 = math.Min(t1, r)
+		}
+		return true
+	}
+	if !(clip(-dE, a.E-e.West) && clip(dE, e.East-a.E) && clip(-dN, a.N-e.South) && clip(dN, e.North-a.N) && t0 <= t1) {
+		return PointM{}, PointM{}, false
+	}
+	ca, cb := a, b
+	if t0 > 0 {
+		ca = PointM{E: a.E + t0*dE, N: a.N + t0*dN}
+	}
+	if t1 < 1 {
+		cb = PointM{E: a.E + t1*dE, N: a.N + t1*dN}
+	}
+	return ca, cb, true
+}
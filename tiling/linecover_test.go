@@ -0,0 +1,79 @@
+package tiling_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestLineTiles(t *testing.T) {
+	lines := [][]tiling.PointM{
+		{tilePoint(0.5, 0.5, 3), tilePoint(3.5, 0.5, 3)},
+		{tilePoint(0.5, 0.5, 3), tilePoint(2.5, 1.5, 3)},
+		{tilePoint(2.5, 2.5, 3), tilePoint(0.5, 2.5, 3), tilePoint(0.5, 0.5, 3), tilePoint(2.5, 2.5, 3)},
+		{tilePoint(4.5, 4.5, 3)},
+	}
+	expected := [][]tiling.Tile{
+		{{X: 0, Y: 0, Z: 3}, {X: 1, Y: 0, Z: 3}, {X: 2, Y: 0, Z: 3}, {X: 3, Y: 0, Z: 3}},
+		{{X: 0, Y: 0, Z: 3}, {X: 1, Y: 0, Z: 3}, {X: 1, Y: 1, Z: 3}, {X: 2, Y: 1, Z: 3}},
+		{
+			{X: 2, Y: 2, Z: 3}, {X: 1, Y: 2, Z: 3}, {X: 0, Y: 2, Z: 3}, {X: 0, Y: 1, Z: 3}, {X: 0, Y: 0, Z: 3},
+			{X: 1, Y: 1, Z: 3},
+		},
+		{{X: 4, Y: 4, Z: 3}},
+	}
+	for i, l := range lines {
+		t.Run(fmt.Sprintf("Line %d", i), func(t *testing.T) {
+			ts := tiling.NewZoomLevel(3).LineTiles(l, 0)
+			if len(ts) != len(expected[i]) {
+				t.Fatalf("Got %d tiles instead of %d: %+v", len(ts), len(expected[i]), ts)
+			}
+			for j := range ts {
+				if ts[j] != expected[i][j] {
+					t.Errorf("Tile %d is different (expected, actual) %+v != %+v", j, expected[i][j], ts[j])
+				}
+			}
+		})
+	}
+}
+
+func TestLineTilesBuffer(t *testing.T) {
+	zl := tiling.NewZoomLevel(5)
+	line := []tiling.PointM{tilePoint(3.5, 3.5, 5), tilePoint(8.5, 3.5, 5)}
+	plain := zl.LineTiles(line, 0)
+	if len(plain) != 6 {
+		t.Fatalf("Got %d tiles instead of 6: %+v", len(plain), plain)
+	}
+	small := zl.LineTilesPx(line, 100)
+	if len(small) != 6 {
+		t.Errorf("A buffer within the tiles should not add tiles: %+v", small)
+	}
+	wide := zl.LineTilesPx(line, 200)
+	if len(wide) != 8*3 {
+		t.Errorf("Got %d tiles instead of %d: %+v", len(wide), 8*3, wide)
+	}
+	if wide[0] != (tiling.Tile{X: 2, Y: 3, Z: 5}) {
+		t.Errorf("Buffered tiles are not ordered along the line: %+v", wide)
+	}
+	side := tiling.ExtentOf(tiling.Tile{Z: 5}).East - tiling.ExtentOf(tiling.Tile{Z: 5}).West
+	//ground meters are shorter than mercator meters by the cosine of the latitude
+	cos := math.Cos(tiling.MercToGeo(line[0]).Lat * math.Pi / 180)
+	meters := zl.LineTiles(line, side*0.6*cos)
+	if len(meters) != 8*3-4 {
+		t.Errorf("Got %d tiles instead of %d: %+v", len(meters), 8*3-4, meters)
+	}
+	if projected := zl.LineTiles(line, side*0.6); len(projected) <= len(meters) {
+		t.Errorf("A ground buffer should cover more tiles than the same mercator buffer: %d <= %d", len(projected), len(meters))
+	}
+}
+
+func TestGeoToMercLine(t *testing.T) {
+	line := tiling.GeoToMercLine([]tiling.PointG{{Lat: 45.4498397, Lon: 9.1682557}, {Lat: 90, Lon: 9.1682557}})
+	ts := tiling.NewZoomLevel(2).LineTiles(line, 0)
+	expected := []tiling.Tile{{X: 2, Y: 1, Z: 2}, {X: 2, Y: 0, Z: 2}}
+	if len(ts) != len(expected) || ts[0] != expected[0] || ts[1] != expected[1] {
+		t.Errorf("Tiles are different (expected, actual) %+v != %+v", expected, ts)
+	}
+}
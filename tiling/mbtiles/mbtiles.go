@@ -0,0 +1,244 @@
+//Package mbtiles reads and writes MBTiles 1.3 files https://github.com/mapbox/mbtiles-spec
+//addressing tiles with tiling.Tile in the XYZ scheme.
+//
+//The package uses database/sql and does not import any SQLite driver:
+//register one (e.g. github.com/mattn/go-sqlite3) and pass its name to Open and Create.
+package mbtiles
+
+import (
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//ErrTileNotFound is returned when reading a tile missing from the file
+var ErrTileNotFound = errors.New("Tile not found")
+
+const simpleSchema = `
+CREATE TABLE IF NOT EXISTS metadata (name TEXT, value TEXT);
+CREATE UNIQUE INDEX IF NOT EXISTS metadata_name ON metadata (name);
+CREATE TABLE IF NOT EXISTS tiles (zoom_level INTEGER, tile_column INTEGER, tile_row INTEGER, tile_data BLOB);
+CREATE UNIQUE INDEX IF NOT EXISTS tile_index ON tiles (zoom_level, tile_column, tile_row);
+`
+
+const dedupSchema = `
+CREATE TABLE IF NOT EXISTS metadata (name TEXT, value TEXT);
+CREATE UNIQUE INDEX IF NOT EXISTS metadata_name ON metadata (name);
+CREATE TABLE IF NOT EXISTS map (zoom_level INTEGER, tile_column INTEGER, tile_row INTEGER, tile_id TEXT);
+CREATE UNIQUE INDEX IF NOT EXISTS map_index ON map (zoom_level, tile_column, tile_row);
+CREATE INDEX IF NOT EXISTS map_tile_id ON map (tile_id);
+CREATE TABLE IF NOT EXISTS images (tile_data BLOB, tile_id TEXT);
+CREATE UNIQUE INDEX IF NOT EXISTS images_id ON images (tile_id);
+CREATE VIEW IF NOT EXISTS tiles AS
+	SELECT map.zoom_level AS zoom_level, map.tile_column AS tile_column, map.tile_row AS tile_row, images.tile_data AS tile_data
+	FROM map JOIN images ON images.tile_id = map.tile_id;
+`
+
+//MBTiles is an open MBTiles file
+type MBTiles struct {
+	db    *sql.DB
+	dedup bool
+}
+
+//Create creates, or opens if it exists, the MBTiles file at path with the given SQLite driver.
+//When dedup is true tiles are stored once per content in the images table and referenced by the map table.
+func Create(driver, path string, dedup bool) (*MBTiles, error) {
+	db, err := sql.Open(driver, path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open %s: %v", path, err)
+	}
+	m, err := newMBTiles(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if m.dedup != dedup && m.hasTable("tiles") {
+		db.Close()
+		return nil, fmt.Errorf("File %s already exists with a different schema", path)
+	}
+	schema := simpleSchema
+	if dedup {
+		schema = dedupSchema
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Cannot create the MBTiles schema in %s: %v", path, err)
+	}
+	m.dedup = dedup
+	return m, nil
+}
+
+//Open opens the existing MBTiles file at path with the given SQLite driver
+func Open(driver, path string) (*MBTiles, error) {
+	db, err := sql.Open(driver, path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open %s: %v", path, err)
+	}
+	m, err := newMBTiles(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !m.hasTable("tiles") || !m.hasTable("metadata") {
+		db.Close()
+		return nil, fmt.Errorf("File %s is not an MBTiles file", path)
+	}
+	return m, nil
+}
+
+func newMBTiles(db *sql.DB) (*MBTiles, error) {
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("Cannot connect to the database: %v", err)
+	}
+	m := &MBTiles{db: db}
+	m.dedup = m.hasTable("map") && m.hasTable("images")
+	return m, nil
+}
+
+//hasTable tells if a table or view with the given name exists
+func (m *MBTiles) hasTable(name string) bool {
+	var n int
+	err := m.db.QueryRow("SELECT count(*) FROM sqlite_master WHERE name = ? AND type IN ('table', 'view')", name).Scan(&n)
+	return err == nil && n > 0
+}
+
+//Close closes the file
+func (m *MBTiles) Close() error {
+	return m.db.Close()
+}
+
+//Deduplicated tells if the file uses the images/map schema
+func (m *MBTiles) Deduplicated() bool {
+	return m.dedup
+}
+
+//tmsRow gives the MBTiles row of the tile, which follows the TMS scheme
+func tmsRow(t tiling.Tile) int {
+	return tiling.FlipY(t.Y, t.Z)
+}
+
+//WriteTile stores the data of the tile, replacing the previous one
+func (m *MBTiles) WriteTile(t tiling.Tile, data []byte) error {
+	var err error
+	if m.dedup {
+		sum := md5.Sum(data)
+		id := hex.EncodeToString(sum[:])
+		err = m.inTx(func(tx *sql.Tx) error {
+			old, err := mappedImage(tx, t)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec("INSERT OR IGNORE INTO images (tile_data, tile_id) VALUES (?, ?)", data, id); err != nil {
+				return err
+			}
+			if _, err := tx.Exec("INSERT OR REPLACE INTO map (zoom_level, tile_column, tile_row, tile_id) VALUES (?, ?, ?, ?)",
+				t.Z, t.X, tmsRow(t), id); err != nil {
+				return err
+			}
+			if old == "" || old == id {
+				return nil
+			}
+			return deleteUnusedImage(tx, old)
+		})
+	} else {
+		_, err = m.db.Exec("INSERT OR REPLACE INTO tiles (zoom_level, tile_column, tile_row, tile_data) VALUES (?, ?, ?, ?)",
+			t.Z, t.X, tmsRow(t), data)
+	}
+	if err != nil {
+		return fmt.Errorf("Cannot write tile (x, y, z)(%d, %d, %d): %v", t.X, t.Y, t.Z, err)
+	}
+	return nil
+}
+
+//ReadTile gives the data of the tile or ErrTileNotFound
+func (m *MBTiles) ReadTile(t tiling.Tile) ([]byte, error) {
+	var data []byte
+	err := m.db.QueryRow("SELECT tile_data FROM tiles WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?",
+		t.Z, t.X, tmsRow(t)).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, ErrTileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read tile (x, y, z)(%d, %d, %d): %v", t.X, t.Y, t.Z, err)
+	}
+	return data, nil
+}
+
+//mappedImage gives the id of the image of the tile in the map table, empty if the tile is missing
+func mappedImage(tx *sql.Tx, t tiling.Tile) (string, error) {
+	var id string
+	err := tx.QueryRow("SELECT tile_id FROM map WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?",
+		t.Z, t.X, tmsRow(t)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return id, err
+}
+
+//deleteUnusedImage removes the image with the given id if no tile of the map table references it anymore
+func deleteUnusedImage(tx *sql.Tx, id string) error {
+	_, err := tx.Exec("DELETE FROM images WHERE tile_id = ? AND NOT EXISTS (SELECT 1 FROM map WHERE tile_id = ?)", id, id)
+	return err
+}
+
+//DeleteTile removes the tile, images no longer referenced are removed as well
+func (m *MBTiles) DeleteTile(t tiling.Tile) error {
+	var err error
+	if m.dedup {
+		err = m.inTx(func(tx *sql.Tx) error {
+			old, err := mappedImage(tx, t)
+			if err != nil || old == "" {
+				return err
+			}
+			if _, err := tx.Exec("DELETE FROM map WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?", t.Z, t.X, tmsRow(t)); err != nil {
+				return err
+			}
+			return deleteUnusedImage(tx, old)
+		})
+	} else {
+		_, err = m.db.Exec("DELETE FROM tiles WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?", t.Z, t.X, tmsRow(t))
+	}
+	if err != nil {
+		return fmt.Errorf("Cannot delete tile (x, y, z)(%d, %d, %d): %v", t.X, t.Y, t.Z, err)
+	}
+	return nil
+}
+
+//EachTile calls fn for every tile of the file, ordered by zoom, column and row, until fn returns an error
+func (m *MBTiles) EachTile(fn func(t tiling.Tile, data []byte) error) error {
+	rows, err := m.db.Query("SELECT zoom_level, tile_column, tile_row, tile_data FROM tiles ORDER BY zoom_level, tile_column, tile_row")
+	if err != nil {
+		return fmt.Errorf("Cannot list tiles: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t tiling.Tile
+		var row int
+		var data []byte
+		if err := rows.Scan(&t.Z, &t.X, &row, &data); err != nil {
+			return fmt.Errorf("Cannot list tiles: %v", err)
+		}
+		t.Y = tiling.FlipY(row, t.Z)
+		if err := fn(t, data); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+//inTx runs fn in a transaction, committed only if fn succeeds
+func (m *MBTiles) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,203 @@
+package mbtiles_test
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/mbtiles"
+)
+
+func tempFile(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "mbtiles")
+	if err != nil {
+		t.Fatalf("Cannot create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "test.mbtiles")
+}
+
+func TestReadWriteTiles(t *testing.T) {
+	for _, dedup := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Dedup %v", dedup), func(t *testing.T) {
+			path := tempFile(t)
+			m, err := mbtiles.Create("sqlite3", path, dedup)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tiles := []tiling.Tile{
+				tiling.Tile{X: 0, Y: 0, Z: 0},
+				tiling.Tile{X: 33, Y: 23, Z: 6},
+				tiling.Tile{X: 34, Y: 23, Z: 6},
+			}
+			blobs := [][]byte{[]byte("root"), []byte("same"), []byte("same")}
+			for i, tl := range tiles {
+				if err := m.WriteTile(tl, blobs[i]); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+			m.Close()
+
+			m, err = mbtiles.Open("sqlite3", path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer m.Close()
+			if m.Deduplicated() != dedup {
+				t.Errorf("Schema detection failed, dedup is %v", m.Deduplicated())
+			}
+			for i, tl := range tiles {
+				data, err := m.ReadTile(tl)
+				if err != nil || !bytes.Equal(data, blobs[i]) {
+					t.Errorf("Tile %+v is different (expected, actual) %q != %q %v", tl, blobs[i], data, err)
+				}
+			}
+			if _, err := m.ReadTile(tiling.Tile{X: 33, Y: 40, Z: 6}); err != mbtiles.ErrTileNotFound {
+				t.Errorf("Expected ErrTileNotFound, got %v", err)
+			}
+			if err := m.DeleteTile(tiles[1]); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := m.ReadTile(tiles[1]); err != mbtiles.ErrTileNotFound {
+				t.Errorf("Deleted tile is still there: %v", err)
+			}
+			if data, err := m.ReadTile(tiles[2]); err != nil || string(data) != "same" {
+				t.Errorf("Shared image was removed: %q %v", data, err)
+			}
+			var seen []tiling.Tile
+			err = m.EachTile(func(tl tiling.Tile, data []byte) error {
+				seen = append(seen, tl)
+				return nil
+			})
+			if err != nil || len(seen) != 2 || seen[0] != tiles[0] || seen[1] != tiles[2] {
+				t.Errorf("Listed tiles are different: %+v %v", seen, err)
+			}
+			if min, max, err := m.ZoomRange(); err != nil || min != 0 || max != 6 {
+				t.Errorf("Zoom range is different (expected, actual) 0-6 != %d-%d %v", min, max, err)
+			}
+		})
+	}
+}
+
+func TestDedupImages(t *testing.T) {
+	path := tempFile(t)
+	m, err := mbtiles.Create("sqlite3", path, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Close()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+	images := func() int {
+		var n int
+		if err := db.QueryRow("SELECT count(*) FROM images").Scan(&n); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return n
+	}
+	a, b := tiling.Tile{X: 1, Y: 1, Z: 1}, tiling.Tile{X: 0, Y: 1, Z: 1}
+	steps := []struct {
+		name   string
+		op     func() error
+		images int
+	}{
+		{"write a", func() error { return m.WriteTile(a, []byte("first")) }, 1},
+		{"rewrite a", func() error { return m.WriteTile(a, []byte("second")) }, 1},
+		{"rewrite a with the same data", func() error { return m.WriteTile(a, []byte("second")) }, 1},
+		{"write b sharing the image", func() error { return m.WriteTile(b, []byte("second")) }, 1},
+		{"rewrite a keeping the shared image", func() error { return m.WriteTile(a, []byte("third")) }, 2},
+		{"delete b", func() error { return m.DeleteTile(b) }, 1},
+		{"delete a missing tile", func() error { return m.DeleteTile(b) }, 1},
+		{"delete a", func() error { return m.DeleteTile(a) }, 0},
+	}
+	for _, s := range steps {
+		if err := s.op(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", s.name, err)
+		}
+		if n := images(); n != s.images {
+			t.Errorf("%s: images are different (expected, actual) %d != %d", s.name, s.images, n)
+		}
+	}
+}
+
+func TestTMSRows(t *testing.T) {
+	path := tempFile(t)
+	m, err := mbtiles.Create("sqlite3", path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := m.WriteTile(tiling.Tile{X: 33, Y: 23, Z: 6}, []byte("x")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m.Close()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+	var row int
+	if err := db.QueryRow("SELECT tile_row FROM tiles WHERE zoom_level = 6 AND tile_column = 33").Scan(&row); err != nil || row != 40 {
+		t.Errorf("Row is not stored in TMS scheme (expected, actual) 40 != %d %v", row, err)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	m, err := mbtiles.Create("sqlite3", tempFile(t), true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Close()
+	md := mbtiles.Metadata{
+		Name:    "test",
+		Format:  "png",
+		Bounds:  tiling.ExtentG{MinLon: -10.5, MinLat: 35, MaxLon: 20, MaxLat: 60.25},
+		MinZoom: 2,
+		MaxZoom: 14,
+		Type:    "baselayer",
+		Extra:   map[string]string{"json": "{}"},
+	}
+	if _, _, _, ok := m.Describe(); ok {
+		t.Errorf("A file without metadata should not be described")
+	}
+	if err := m.WriteMetadata(md); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if b, min, max, ok := m.Describe(); !ok || b != md.Bounds || min != 2 || max != 14 {
+		t.Errorf("Description is different (expected, actual) %+v 2-14 != %+v %d-%d %v", md.Bounds, b, min, max, ok)
+	}
+	read, err := m.ReadMetadata()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if read.Name != md.Name || read.Format != md.Format || read.Bounds != md.Bounds || read.MinZoom != 2 || read.MaxZoom != 14 ||
+		read.Type != md.Type || read.Extra["json"] != "{}" || len(read.Extra) != 1 {
+		t.Errorf("Metadata is different (expected, actual) %+v != %+v", md, read)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	path := tempFile(t)
+	if _, err := mbtiles.Open("sqlite3", path); err == nil {
+		t.Errorf("Opening an empty file should fail")
+	}
+	m, err := mbtiles.Create("sqlite3", path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m.Close()
+	if _, err := mbtiles.Create("sqlite3", path, true); err == nil {
+		t.Errorf("Changing the schema of an existing file should fail")
+	}
+	if _, err := mbtiles.Open("nodriver", path); err == nil {
+		t.Errorf("Unknown driver should fail")
+	}
+}
@@ -0,0 +1,171 @@
+package mbtiles
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//Metadata is the content of the metadata table, keys not described by a field are kept in Extra
+type Metadata struct {
+	Name        string
+	Format      string
+	Bounds      tiling.ExtentG
+	MinZoom     int
+	MaxZoom     int
+	Attribution string
+	Description string
+	Type        string
+	Version     string
+	Extra       map[string]string
+}
+
+//WriteMetadata replaces the metadata table with the given metadata
+func (m *MBTiles) WriteMetadata(md Metadata) error {
+	values := map[string]string{}
+	for k, v := range md.Extra {
+		values[k] = v
+	}
+	set := func(k, v string) {
+		if v != "" {
+			values[k] = v
+		}
+	}
+	set("name", md.Name)
+	set("format", md.Format)
+	set("attribution", md.Attribution)
+	set("description", md.Description)
+	set("type", md.Type)
+	set("version", md.Version)
+	if md.Bounds != (tiling.ExtentG{}) {
+		values["bounds"] = formatBounds(md.Bounds)
+	}
+	values["minzoom"] = strconv.Itoa(md.MinZoom)
+	values["maxzoom"] = strconv.Itoa(md.MaxZoom)
+	err := m.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM metadata"); err != nil {
+			return err
+		}
+		for k, v := range values {
+			if _, err := tx.Exec("INSERT INTO metadata (name, value) VALUES (?, ?)", k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Cannot write metadata: %v", err)
+	}
+	return nil
+}
+
+//ReadMetadata gives the content of the metadata table
+func (m *MBTiles) ReadMetadata() (Metadata, error) {
+	md := Metadata{Extra: map[string]string{}}
+	rows, err := m.db.Query("SELECT name, value FROM metadata")
+	if err != nil {
+		return md, fmt.Errorf("Cannot read metadata: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return md, fmt.Errorf("Cannot read metadata: %v", err)
+		}
+		switch k {
+		case "name":
+			md.Name = v
+		case "format":
+			md.Format = v
+		case "attribution":
+			md.Attribution = v
+		case "description":
+			md.Description = v
+		case "type":
+			md.Type = v
+		case "version":
+			md.Version = v
+		case "bounds":
+			if md.Bounds, err = parseBounds(v); err != nil {
+				return md, err
+			}
+		case "minzoom":
+			if md.MinZoom, err = strconv.Atoi(v); err != nil {
+				return md, fmt.Errorf("Invalid minzoom %q: %v", v, err)
+			}
+		case "maxzoom":
+			if md.MaxZoom, err = strconv.Atoi(v); err != nil {
+				return md, fmt.Errorf("Invalid maxzoom %q: %v", v, err)
+			}
+		default:
+			md.Extra[k] = v
+		}
+	}
+	return md, rows.Err()
+}
+
+//Describe gives the bounds and the zoom range recorded in the metadata table, ok is false when any of them is
+//missing or invalid. It implements store.Describer for stores backed by the file.
+func (m *MBTiles) Describe() (tiling.ExtentG, int, int, bool) {
+	rows, err := m.db.Query("SELECT name, value FROM metadata WHERE name IN ('bounds', 'minzoom', 'maxzoom')")
+	if err != nil {
+		return tiling.ExtentG{}, 0, 0, false
+	}
+	defer rows.Close()
+	values := map[string]string{}
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return tiling.ExtentG{}, 0, 0, false
+		}
+		values[k] = v
+	}
+	bounds, err := parseBounds(values["bounds"])
+	if err != nil || rows.Err() != nil {
+		return tiling.ExtentG{}, 0, 0, false
+	}
+	minZoom, errMin := strconv.Atoi(values["minzoom"])
+	maxZoom, errMax := strconv.Atoi(values["maxzoom"])
+	if errMin != nil || errMax != nil || minZoom < 0 || maxZoom < minZoom {
+		return tiling.ExtentG{}, 0, 0, false
+	}
+	return bounds, minZoom, maxZoom, true
+}
+
+//ZoomRange gives the minimum and maximum zoom levels of the stored tiles
+func (m *MBTiles) ZoomRange() (int, int, error) {
+	var min, max sql.NullInt64
+	if err := m.db.QueryRow("SELECT min(zoom_level), max(zoom_level) FROM tiles").Scan(&min, &max); err != nil {
+		return 0, 0, fmt.Errorf("Cannot read zoom range: %v", err)
+	}
+	if !min.Valid {
+		return 0, 0, ErrTileNotFound
+	}
+	return int(min.Int64), int(max.Int64), nil
+}
+
+//formatBounds writes the extent as "left,bottom,right,top" in degrees
+func formatBounds(e tiling.ExtentG) string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return strings.Join([]string{f(e.MinLon), f(e.MinLat), f(e.MaxLon), f(e.MaxLat)}, ",")
+}
+
+//parseBounds reads an extent written as "left,bottom,right,top" in degrees
+func parseBounds(s string) (tiling.ExtentG, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return tiling.ExtentG{}, fmt.Errorf("Invalid bounds %q: 4 values are needed", s)
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return tiling.ExtentG{}, fmt.Errorf("Invalid bounds %q: %v", s, err)
+		}
+		v[i] = f
+	}
+	return tiling.ExtentG{MinLon: v[0], MinLat: v[1], MaxLon: v[2], MaxLat: v[3]}, nil
+}
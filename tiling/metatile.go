@@ -0,0 +1,147 @@
+package tiling
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+//DefaultMetatileSize is the number of tiles per side of the metatiles rendered by mapnik-style renderers
+const DefaultMetatileSize = 8
+
+//Metatile groups Size×Size XYZ tiles of zoom level Z, X and Y number the metatiles from the upper left corner.
+//At the zoom levels with less than Size tiles per side the metatile holds the whole zoom level.
+type Metatile struct {
+	X    int
+	Y    int
+	Z    int
+	Size int
+}
+
+//MetatileOf gives the metatile of the given size, at least 1, containing the XYZ tile t
+func MetatileOf(t Tile, size int) (Metatile, error) {
+	if size < 1 {
+		return Metatile{}, fmt.Errorf("Invalid metatile size %d", size)
+	}
+	if t.Z < 0 || t.Z >= maxQuadkeyZoom || t.X < 0 || t.Y < 0 || t.X >= 1<<uint(t.Z) || t.Y >= 1<<uint(t.Z) {
+		return Metatile{}, fmt.Errorf("Tile (x, y, z)(%d, %d, %d) out of the zoom level", t.X, t.Y, t.Z)
+	}
+	return Metatile{X: t.X / size, Y: t.Y / size, Z: t.Z, Size: size}, nil
+}
+
+//check verifies that the size is at least 1 and that the metatile lies in its zoom level
+func (m Metatile) check() error {
+	if m.Size < 1 {
+		return fmt.Errorf("Invalid metatile size %d", m.Size)
+	}
+	if m.Z < 0 || m.Z >= maxQuadkeyZoom {
+		return fmt.Errorf("Metatile zoom %d out of range [0, %d]", m.Z, maxQuadkeyZoom-1)
+	}
+	count := (1<<uint(m.Z)-1)/m.Size + 1
+	if m.X < 0 || m.Y < 0 || m.X >= count || m.Y >= count {
+		return fmt.Errorf("Metatile (x, y, z)(%d, %d, %d) of size %d out of the zoom level", m.X, m.Y, m.Z, m.Size)
+	}
+	return nil
+}
+
+//Range gives the XYZ range of the member tiles, clipped to the zoom level bounds
+func (m Metatile) Range() (Range, error) {
+	if err := m.check(); err != nil {
+		return Range{}, err
+	}
+	last := 1<<uint(m.Z) - 1
+	return Range{
+		MinX: m.X * m.Size,
+		MaxX: minInt(m.X*m.Size+m.Size-1, last),
+		MinY: m.Y * m.Size,
+		MaxY: minInt(m.Y*m.Size+m.Size-1, last),
+		ZL:   m.Z,
+	}, nil
+}
+
+//Tiles gives the XYZ member tiles, row by row
+func (m Metatile) Tiles() ([]Tile, error) {
+	r, err := m.Range()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Tile, 0, r.Cardinality())
+	r.Each(func(t Tile) bool {
+		res = append(res, t)
+		return true
+	})
+	return res, nil
+}
+
+//rangeOfMetatile gives the range of the metatile, which must belong to the zoom level
+func (z *ZoomLevel) rangeOfMetatile(m Metatile) (Range, error) {
+	if m.Z != z.zoom {
+		return Range{}, fmt.Errorf("Metatile zoom %d is not the zoom level %d", m.Z, z.zoom)
+	}
+	return m.Range()
+}
+
+//ExtentOfMetatile return the mercator extent of the given metatile, which must belong to the zoom level
+func (z *ZoomLevel) ExtentOfMetatile(m Metatile) (ExtentM, error) {
+	r, err := z.rangeOfMetatile(m)
+	if err != nil {
+		return ExtentM{}, err
+	}
+	ul := z.xyzExtentOfTile(r.MinX, r.MinY).UL()
+	lr := z.xyzExtentOfTile(r.MaxX, r.MaxY).LR()
+	return NewExtentM(ul, lr), nil
+}
+
+//BufferedExtentOfMetatile return the mercator extent of the given metatile enlarged on every side by buffer pixels
+//of the zoom level, as rendered to avoid cut labels and symbols on the borders
+func (z *ZoomLevel) BufferedExtentOfMetatile(m Metatile, buffer int) (ExtentM, error) {
+	ext, err := z.ExtentOfMetatile(m)
+	if err != nil {
+		return ExtentM{}, err
+	}
+	b := float64(buffer) * z.Resolution()
+	return ExtentM{North: ext.North + b, South: ext.South - b, East: ext.East + b, West: ext.West - b}, nil
+}
+
+//MetatilePixelSize gives the width and height in pixels of the rendered metatile with the given buffer
+func (z *ZoomLevel) MetatilePixelSize(m Metatile, buffer int) (int, int, error) {
+	r, err := z.rangeOfMetatile(m)
+	if err != nil {
+		return 0, 0, err
+	}
+	return r.Width()*z.size + 2*buffer, r.Height()*z.size + 2*buffer, nil
+}
+
+//SliceMetatile cuts the image rendered for the buffered extent of the metatile in the images of its member tiles,
+//which are numbered according to the zoom level scheme. Tile images share the pixels of img when it supports SubImage.
+func (z *ZoomLevel) SliceMetatile(m Metatile, img image.Image, buffer int) (map[Tile]image.Image, error) {
+	w, h, err := z.MetatilePixelSize(m, buffer)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	if b.Dx() != w || b.Dy() != h {
+		return nil, fmt.Errorf("Metatile image is %dx%d pixels instead of %dx%d", b.Dx(), b.Dy(), w, h)
+	}
+	r, _ := m.Range()
+	res := make(map[Tile]image.Image, r.Cardinality())
+	r.Each(func(t Tile) bool {
+		min := b.Min.Add(image.Pt(buffer+(t.X-r.MinX)*z.size, buffer+(t.Y-r.MinY)*z.size))
+		rect := image.Rectangle{Min: min, Max: min.Add(image.Pt(z.size, z.size))}
+		res[ConvertTile(t, XYZ, z.scheme)] = subImage(img, rect)
+		return true
+	})
+	return res, nil
+}
+
+//subImage gives the part of img inside rect, copying the pixels when img does not support SubImage
+func subImage(img image.Image, rect image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(rect)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,136 @@
+package tiling_test
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestMetatileOf(t *testing.T) {
+	tiles := []tiling.Tile{{X: 0, Y: 0, Z: 0}, {X: 3, Y: 2, Z: 2}, {X: 550, Y: 335, Z: 10}, {X: 7, Y: 8, Z: 4}}
+	metas := []tiling.Metatile{{X: 0, Y: 0, Z: 0, Size: 8}, {X: 0, Y: 0, Z: 2, Size: 8}, {X: 68, Y: 41, Z: 10, Size: 8}, {X: 0, Y: 1, Z: 4, Size: 8}}
+	cards := []int{1, 16, 64, 64}
+	for i, tl := range tiles {
+		m, err := tiling.MetatileOf(tl, tiling.DefaultMetatileSize)
+		if err != nil || m != metas[i] {
+			t.Errorf("Metatile is different (expected, actual) %+v != %+v %v", metas[i], m, err)
+		}
+		members, err := m.Tiles()
+		if err != nil || len(members) != cards[i] {
+			t.Errorf("Metatile %+v has %d tiles instead of %d", m, len(members), cards[i])
+		}
+		found := false
+		for _, mt := range members {
+			found = found || mt == tl
+			if back, _ := tiling.MetatileOf(mt, tiling.DefaultMetatileSize); back != m {
+				t.Errorf("Member %+v does not map back to %+v", mt, m)
+			}
+		}
+		if !found {
+			t.Errorf("Tile %+v is not a member of its metatile", tl)
+		}
+	}
+	for _, size := range []int{0, -8} {
+		if m, err := tiling.MetatileOf(tiling.Tile{X: 3, Y: 2, Z: 2}, size); err == nil {
+			t.Errorf("Size %d should fail, got %+v", size, m)
+		}
+	}
+	if m, err := tiling.MetatileOf(tiling.Tile{X: 4, Y: 0, Z: 2}, 2); err == nil {
+		t.Errorf("A tile out of the zoom level should fail, got %+v", m)
+	}
+	invalid := []tiling.Metatile{{X: 0, Y: 0, Z: 2, Size: 0}, {X: 0, Y: 0, Z: 2, Size: -1}, {X: 2, Y: 0, Z: 2, Size: 2}, {X: 0, Y: -1, Z: 2, Size: 2}}
+	for _, m := range invalid {
+		if _, err := m.Tiles(); err == nil {
+			t.Errorf("Metatile %+v should fail", m)
+		}
+		if _, _, err := tiling.NewZoomLevel(2).MetatilePixelSize(m, 0); err == nil {
+			t.Errorf("Pixel size of metatile %+v should fail", m)
+		}
+	}
+}
+
+func TestExtentOfMetatile(t *testing.T) {
+	z := tiling.NewZoomLevel(10)
+	m := tiling.Metatile{X: 68, Y: 41, Z: 10, Size: 8}
+	ext, err := z.ExtentOfMetatile(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ul := z.ExtentOfTile(544, 328)
+	lr := z.ExtentOfTile(551, 335)
+	if !tiling.Equals(ext, tiling.NewExtentM(ul.UL(), lr.LR())) {
+		t.Errorf("Unexpected metatile extent %+v", ext)
+	}
+	buffered, err := z.BufferedExtentOfMetatile(m, 128)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b := 128 * z.Resolution()
+	if math.Abs(buffered.North-ext.North-b) > 1e-6 || math.Abs(ext.West-buffered.West-b) > 1e-6 ||
+		math.Abs(ext.South-buffered.South-b) > 1e-6 || math.Abs(buffered.East-ext.East-b) > 1e-6 {
+		t.Errorf("Unexpected buffered extent %+v", buffered)
+	}
+	world, err := tiling.NewZoomLevel(1).ExtentOfMetatile(tiling.Metatile{Z: 1, Size: 8})
+	if err != nil || !tiling.Equals(world, tiling.ExtentOf(tiling.Tile{})) {
+		t.Errorf("A metatile larger than the zoom level should cover the world: %+v %v", world, err)
+	}
+	if _, err := z.ExtentOfMetatile(tiling.Metatile{Z: 9, Size: 8}); err == nil {
+		t.Errorf("A metatile of another zoom level should fail")
+	}
+}
+
+func TestSliceMetatile(t *testing.T) {
+	m := tiling.Metatile{X: 1, Y: 0, Z: 3, Size: 4}
+	for _, scheme := range []tiling.Scheme{tiling.XYZ, tiling.TMS} {
+		z := tiling.NewZoomLevel(3).WithScheme(scheme).WithTileSize(16)
+		w, h, err := z.MetatilePixelSize(m, 8)
+		if err != nil || w != 80 || h != 80 {
+			t.Fatalf("Unexpected metatile size %dx%d %v", w, h, err)
+		}
+		img := image.NewGray(image.Rect(10, 20, 10+w, 20+h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				c := uint8(255)
+				if x >= 8 && y >= 8 && x < w-8 && y < h-8 {
+					c = uint8(((y-8)/16)*4 + (x-8)/16)
+				}
+				img.SetGray(10+x, 20+y, color.Gray{Y: c})
+			}
+		}
+		tiles, err := z.SliceMetatile(m, img, 8)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(tiles) != 16 {
+			t.Fatalf("Expected 16 tiles, got %d", len(tiles))
+		}
+		members, _ := m.Tiles()
+		for _, xyz := range members {
+			tl := tiling.ConvertTile(xyz, tiling.XYZ, scheme)
+			ti, ok := tiles[tl]
+			if !ok {
+				t.Fatalf("Missing tile %+v", tl)
+			}
+			b := ti.Bounds()
+			if b.Dx() != 16 || b.Dy() != 16 {
+				t.Errorf("Tile %+v is %dx%d", tl, b.Dx(), b.Dy())
+			}
+			expected := uint8(xyz.Y*4 + xyz.X - 4)
+			for _, p := range []image.Point{b.Min, b.Max.Sub(image.Pt(1, 1))} {
+				if c := color.GrayModel.Convert(ti.At(p.X, p.Y)).(color.Gray).Y; c != expected {
+					t.Errorf("Tile %+v pixel %v is %d instead of %d", tl, p, c, expected)
+				}
+			}
+		}
+	}
+	z := tiling.NewZoomLevel(3)
+	if _, err := z.SliceMetatile(m, image.NewGray(image.Rect(0, 0, 1024, 1024)), 8); err == nil {
+		t.Errorf("A wrong image size should fail")
+	}
+	if _, err := z.SliceMetatile(tiling.Metatile{Z: 3, Size: 0}, image.NewGray(image.Rect(0, 0, 16, 16)), 8); err == nil {
+		t.Errorf("A zero metatile size should fail")
+	}
+}
@@ -0,0 +1,148 @@
+package tiling
+
+import "math"
+
+//DefaultMVTExtent is the side of the integer grid of a vector tile suggested by the MVT specification
+const DefaultMVTExtent = 4096
+
+//DefaultMVTBuffer is the usual buffer, in grid units, kept around a vector tile of DefaultMVTExtent
+const DefaultMVTBuffer = 64
+
+//MVTPoint is a point in the integer grid of a vector tile: origin in the upper left corner of the tile,
+//X grows eastward and Y southward. Points of the buffer have negative coordinates or coordinates beyond the extent.
+type MVTPoint struct {
+	X int
+	Y int
+}
+
+//MVTGrid transforms coordinates between mercator and the integer grid of a vector tile
+type MVTGrid struct {
+	Tile   Tile
+	Extent int
+	Buffer int
+	bounds ExtentM
+}
+
+//MVTGrid gives the grid of the tile (x, y), read according to the zoom level scheme, divided in extent units per side
+//and surrounded by buffer units
+func (z *ZoomLevel) MVTGrid(x, y, extent, buffer int) MVTGrid {
+	return MVTGrid{Tile: Tile{X: x, Y: y, Z: z.zoom}, Extent: extent, Buffer: buffer, bounds: z.ExtentOfTile(x, y)}
+}
+
+//unit gives the size in mercator meters of a grid unit
+func (g MVTGrid) unit() float64 {
+	return (g.bounds.East - g.bounds.West) / float64(g.Extent)
+}
+
+//TileExtent return the mercator extent of the tile
+func (g MVTGrid) TileExtent() ExtentM {
+	return g.bounds
+}
+
+//BufferedExtent return the mercator extent of the tile enlarged by the buffer on every side
+func (g MVTGrid) BufferedExtent() ExtentM {
+	b := float64(g.Buffer) * g.unit()
+	return ExtentM{North: g.bounds.North + b, South: g.bounds.South - b, East: g.bounds.East + b, West: g.bounds.West - b}
+}
+
+//FromMerc gives the grid point nearest to the given mercator point
+func (g MVTGrid) FromMerc(m PointM) MVTPoint {
+	u := g.unit()
+	return MVTPoint{X: int(math.Round((m.E - g.bounds.West) / u)), Y: int(math.Round((g.bounds.North - m.N) / u))}
+}
+
+//ToMerc gives the mercator point of the given grid point
+func (g MVTGrid) ToMerc(p MVTPoint) PointM {
+	u := g.unit()
+	return PointM{E: g.bounds.West + float64(p.X)*u, N: g.bounds.North - float64(p.Y)*u}
+}
+
+//FromGeo gives the grid point nearest to the given geo point
+func (g MVTGrid) FromGeo(p PointG) MVTPoint {
+	return g.FromMerc(GeoToMerc(p))
+}
+
+//ToGeo gives the geo point of the given grid point
+func (g MVTGrid) ToGeo(p MVTPoint) PointG {
+	return MercToGeo(g.ToMerc(p))
+}
+
+//ClipLine cuts the given line to the buffered extent of the tile, it gives the parts of the line inside it
+func (g MVTGrid) ClipLine(line []PointM) [][]PointM {
+	ext := g.BufferedExtent()
+	if len(line) == 1 {
+		if pointRectDistance(line[0], ext) == 0 {
+			return [][]PointM{{line[0]}}
+		}
+		return nil
+	}
+	var res [][]PointM
+	var part []PointM
+	for i := 0; i+1 < len(line); i++ {
+		a, b, ok := clipSegment(line[i], line[i+1], ext)
+		if !ok {
+			continue
+		}
+		if len(part) == 0 || part[len(part)-1] != a {
+			if len(part) > 0 {
+				res = append(res, part)
+			}
+			part = []PointM{a}
+		}
+		part = append(part, b)
+	}
+	if len(part) > 0 {
+		res = append(res, part)
+	}
+	return res
+}
+
+//ClipRing cuts the given polygon ring to the buffered extent of the tile, using Sutherland-Hodgman clipping.
+//The ring may be open or closed, the result is open and nil if nothing of the ring area is left.
+func (g MVTGrid) ClipRing(ring []PointM) []PointM {
+	ext := g.BufferedExtent()
+	if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+		ring = ring[:n-1]
+	}
+	edges := []struct {
+		inside func(PointM) bool
+		cross  func(a, b PointM) PointM
+	}{
+		{func(p PointM) bool { return p.E >= ext.West }, func(a, b PointM) PointM { return crossE(a, b, ext.West) }},
+		{func(p PointM) bool { return p.E <= ext.East }, func(a, b PointM) PointM { return crossE(a, b, ext.East) }},
+		{func(p PointM) bool { return p.N >= ext.South }, func(a, b PointM) PointM { return crossN(a, b, ext.South) }},
+		{func(p PointM) bool { return p.N <= ext.North }, func(a, b PointM) PointM { return crossN(a, b, ext.North) }},
+	}
+	out := ring
+	for _, edge := range edges {
+		in := out
+		out = nil
+		for i, cur := range in {
+			prev := in[(i+len(in)-1)%len(in)]
+			switch {
+			case edge.inside(cur) && edge.inside(prev):
+				out = append(out, cur)
+			case edge.inside(cur):
+				out = append(out, edge.cross(prev, cur), cur)
+			case edge.inside(prev):
+				out = append(out, edge.cross(prev, cur))
+			}
+		}
+	}
+	if len(out) < 3 {
+		return nil
+	}
+	return out
+}
+
+//crossE gives the point of the segment ab with easting e
+func crossE(a, b PointM, e float64) PointM {
+	t := (e - a.E) / (b.E - a.E)
+	return PointM{E: e, N: a.N + t*(b.N-a.N)}
+}
+
+//crossN gives the point of the segment ab with northing n
+func crossN(a, b PointM, n float64) PointM {
+	t := (n - a.N) / (b.N - a.N)
+	return PointM{E: a.E + t*(b.E-a.E), N: n}
+}
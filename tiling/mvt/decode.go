@@ -0,0 +1,199 @@
+package mvt
+
+import (
+	"fmt"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//Decode reads the layers of the vector tile t, polygon rings are returned open
+func Decode(t tiling.Tile, data []byte) ([]Layer, error) {
+	var layers []Layer
+	err := eachField(data, func(f field) error {
+		if f.num != 3 || f.wire != wireBytes {
+			return nil
+		}
+		l, err := decodeLayer(t, f.data)
+		if err != nil {
+			return err
+		}
+		layers = append(layers, l)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Cannot decode vector tile: %v", err)
+	}
+	return layers, nil
+}
+
+//rawFeature is a feature whose tags and geometry wait for the keys, values and extent of the layer
+type rawFeature struct {
+	id   uint64
+	typ  GeomType
+	tags []uint32
+	geom []uint32
+}
+
+func decodeLayer(t tiling.Tile, data []byte) (Layer, error) {
+	var l Layer
+	var keys []string
+	var values []interface{}
+	var raws []rawFeature
+	version := uint64(1)
+	err := eachField(data, func(f field) error {
+		var err error
+		switch f.num {
+		case 15:
+			version = f.val
+		case 1:
+			l.Name = string(f.data)
+		case 2:
+			var rf rawFeature
+			rf, err = decodeFeature(f.data)
+			raws = append(raws, rf)
+		case 3:
+			keys = append(keys, string(f.data))
+		case 4:
+			var v interface{}
+			v, err = decodeValue(f.data)
+			values = append(values, v)
+		case 5:
+			l.Extent = int(f.val)
+		}
+		return err
+	})
+	if err != nil {
+		return Layer{}, err
+	}
+	if version > Version {
+		return Layer{}, fmt.Errorf("Unsupported version %d of layer %s", version, l.Name)
+	}
+	l.Extent = l.extent()
+	grid := l.grid(t)
+	for i, rf := range raws {
+		f := Feature{ID: rf.id, Type: rf.typ}
+		if len(rf.tags)%2 != 0 {
+			return Layer{}, fmt.Errorf("Odd number of tags in feature %d of layer %s", i, l.Name)
+		}
+		if len(rf.tags) > 0 {
+			f.Properties = make(map[string]interface{}, len(rf.tags)/2)
+		}
+		for j := 0; j < len(rf.tags); j += 2 {
+			k, v := rf.tags[j], rf.tags[j+1]
+			if int(k) >= len(keys) || int(v) >= len(values) {
+				return Layer{}, fmt.Errorf("Tag out of range in feature %d of layer %s", i, l.Name)
+			}
+			f.Properties[keys[k]] = values[v]
+		}
+		if err := decodeGeometry(&f, rf.geom, grid); err != nil {
+			return Layer{}, fmt.Errorf("Feature %d of layer %s: %v", i, l.Name, err)
+		}
+		l.Features = append(l.Features, f)
+	}
+	return l, nil
+}
+
+func decodeFeature(data []byte) (rawFeature, error) {
+	var rf rawFeature
+	err := eachField(data, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			rf.id = f.val
+		case 2:
+			rf.tags, err = f.uint32s(rf.tags)
+		case 3:
+			rf.typ = GeomType(f.val)
+		case 4:
+			rf.geom, err = f.uint32s(rf.geom)
+		}
+		return err
+	})
+	return rf, err
+}
+
+func decodeValue(data []byte) (interface{}, error) {
+	var v interface{}
+	err := eachField(data, func(f field) error {
+		switch f.num {
+		case 1:
+			v = string(f.data)
+		case 2:
+			v = f.float32()
+		case 3:
+			v = f.float64()
+		case 4:
+			v = int64(f.val)
+		case 5:
+			v = f.val
+		case 6:
+			v = unzigzag(f.val)
+		case 7:
+			v = f.val != 0
+		}
+		return nil
+	})
+	return v, err
+}
+
+//decodeGeometry reads the geometry commands into the geometry field of f matching its type
+func decodeGeometry(f *Feature, geom []uint32, grid tiling.MVTGrid) error {
+	var x, y int
+	var parts [][]tiling.MVTPoint
+	var part []tiling.MVTPoint
+	for i := 0; i < len(geom); {
+		id, count := int(geom[i]&7), int(geom[i]>>3)
+		i++
+		switch id {
+		case cmdMoveTo, cmdLineTo:
+			if len(geom)-i < 2*count {
+				return fmt.Errorf("Truncated geometry")
+			}
+			if id == cmdMoveTo && f.Type != Point && len(part) > 0 {
+				parts = append(parts, part)
+				part = nil
+			}
+			for j := 0; j < count; j++ {
+				x += int(unzigzag(uint64(geom[i])))
+				y += int(unzigzag(uint64(geom[i+1])))
+				i += 2
+				part = append(part, tiling.MVTPoint{X: x, Y: y})
+			}
+		case cmdClosePath:
+		default:
+			return fmt.Errorf("Unknown geometry command %d", id)
+		}
+	}
+	if len(part) > 0 {
+		parts = append(parts, part)
+	}
+	toGeo := func(ps []tiling.MVTPoint) []tiling.PointG {
+		res := make([]tiling.PointG, len(ps))
+		for i, p := range ps {
+			res[i] = grid.ToGeo(p)
+		}
+		return res
+	}
+	switch f.Type {
+	case Point:
+		for _, p := range parts {
+			f.Points = append(f.Points, toGeo(p)...)
+		}
+	case LineString:
+		for _, p := range parts {
+			f.Lines = append(f.Lines, toGeo(p))
+		}
+	case Polygon:
+		for _, p := range parts {
+			a := ringArea(p)
+			switch {
+			case a > 0:
+				f.Polygons = append(f.Polygons, tiling.PolygonG{toGeo(p)})
+			case a < 0 && len(f.Polygons) > 0:
+				last := len(f.Polygons) - 1
+				f.Polygons[last] = append(f.Polygons[last], toGeo(p))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package mvt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//Encode writes the layers as the vector tile t. Geometries are clipped to the buffered tile,
+//polygon rings are rewound as the spec requires and features left without geometry are dropped.
+func Encode(t tiling.Tile, layers []Layer) ([]byte, error) {
+	var tile []byte
+	for _, l := range layers {
+		data, err := encodeLayer(t, l)
+		if err != nil {
+			return nil, err
+		}
+		tile = appendBytesField(tile, 3, data)
+	}
+	return tile, nil
+}
+
+//layerEncoder collects the features of a layer and their deduplicated keys and values
+type layerEncoder struct {
+	grid     tiling.MVTGrid
+	keys     []string
+	keyIdx   map[string]uint32
+	values   [][]byte
+	valueIdx map[interface{}]uint32
+	features []byte
+}
+
+func encodeLayer(t tiling.Tile, l Layer) ([]byte, error) {
+	if l.Name == "" {
+		return nil, fmt.Errorf("Layer name is required")
+	}
+	e := layerEncoder{grid: l.grid(t), keyIdx: make(map[string]uint32), valueIdx: make(map[interface{}]uint32)}
+	for i, f := range l.Features {
+		if err := e.feature(f); err != nil {
+			return nil, fmt.Errorf("Cannot encode feature %d of layer %s: %v", i, l.Name, err)
+		}
+	}
+	var data []byte
+	data = appendVarintField(data, 15, Version)
+	data = appendBytesField(data, 1, []byte(l.Name))
+	data = append(data, e.features...)
+	for _, k := range e.keys {
+		data = appendBytesField(data, 3, []byte(k))
+	}
+	for _, v := range e.values {
+		data = appendBytesField(data, 4, v)
+	}
+	return appendVarintField(data, 5, uint64(l.extent())), nil
+}
+
+func (e *layerEncoder) feature(f Feature) error {
+	var geom []uint32
+	switch f.Type {
+	case Point:
+		geom = e.points(f.Points)
+	case LineString:
+		geom = e.lines(f.Lines)
+	case Polygon:
+		geom = e.polygons(f.Polygons)
+	default:
+		return fmt.Errorf("Unsupported geometry type %d", f.Type)
+	}
+	if len(geom) == 0 {
+		return nil
+	}
+	tags, err := e.tags(f.Properties)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if f.ID != 0 {
+		data = appendVarintField(data, 1, f.ID)
+	}
+	if len(tags) > 0 {
+		data = appendPackedField(data, 2, tags)
+	}
+	data = appendVarintField(data, 3, uint64(f.Type))
+	data = appendPackedField(data, 4, geom)
+	e.features = appendBytesField(e.features, 2, data)
+	return nil
+}
+
+//tags gives the key and value indexes of the properties, sorted by key
+func (e *layerEncoder) tags(props map[string]interface{}) ([]uint32, error) {
+	keys := make([]string, 0, len(props))
+	for k, v := range props {
+		if v != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	tags := make([]uint32, 0, 2*len(keys))
+	for _, k := range keys {
+		v, data, err := encodeValue(props[k])
+		if err != nil {
+			return nil, fmt.Errorf("Property %s: %v", k, err)
+		}
+		ki, ok := e.keyIdx[k]
+		if !ok {
+			ki = uint32(len(e.keys))
+			e.keyIdx[k] = ki
+			e.keys = append(e.keys, k)
+		}
+		vi, ok := e.valueIdx[v]
+		if !ok {
+			vi = uint32(len(e.values))
+			e.valueIdx[v] = vi
+			e.values = append(e.values, data)
+		}
+		tags = append(tags, ki, vi)
+	}
+	return tags, nil
+}
+
+//encodeValue gives the normalized value used for deduplication and its Value message
+func encodeValue(v interface{}) (interface{}, []byte, error) {
+	switch x := v.(type) {
+	case string:
+		return x, appendBytesField(nil, 1, []byte(x)), nil
+	case float32:
+		b := appendKey(nil, 2, wireFixed32)
+		bits := math.Float32bits(x)
+		return x, append(b, byte(bits), byte(bits>>8), byte(bits>>16), byte(bits>>24)), nil
+	case float64:
+		b := appendKey(nil, 3, wireFixed64)
+		bits := math.Float64bits(x)
+		for i := uint(0); i < 64; i += 8 {
+			b = append(b, byte(bits>>i))
+		}
+		return x, b, nil
+	case int:
+		return encodeInt(int64(x))
+	case int8:
+		return encodeInt(int64(x))
+	case int16:
+		return encodeInt(int64(x))
+	case int32:
+		return encodeInt(int64(x))
+	case int64:
+		return encodeInt(x)
+	case uint:
+		return encodeUint(uint64(x))
+	case uint8:
+		return encodeUint(uint64(x))
+	case uint16:
+		return encodeUint(uint64(x))
+	case uint32:
+		return encodeUint(uint64(x))
+	case uint64:
+		return encodeUint(x)
+	case bool:
+		var n uint64
+		if x {
+			n = 1
+		}
+		return x, appendVarintField(nil, 7, n), nil
+	}
+	return nil, nil, fmt.Errorf("Unsupported value type %T", v)
+}
+
+func encodeInt(v int64) (interface{}, []byte, error) {
+	return v, appendVarintField(nil, 6, zigzag(v)), nil
+}
+
+func encodeUint(v uint64) (interface{}, []byte, error) {
+	return v, appendVarintField(nil, 5, v), nil
+}
+
+//toGrid converts the points to the grid dropping consecutive duplicates
+func (e *layerEncoder) toGrid(ps []tiling.PointM) []tiling.MVTPoint {
+	res := make([]tiling.MVTPoint, 0, len(ps))
+	for _, p := range ps {
+		gp := e.grid.FromMerc(p)
+		if n := len(res); n > 0 && res[n-1] == gp {
+			continue
+		}
+		res = append(res, gp)
+	}
+	return res
+}
+
+func (e *layerEncoder) points(ps []tiling.PointG) []uint32 {
+	min, max := -e.grid.Buffer, e.grid.Extent+e.grid.Buffer
+	var in []tiling.MVTPoint
+	for _, p := range ps {
+		gp := e.grid.FromMerc(tiling.GeoToMerc(p))
+		if gp.X >= min && gp.X <= max && gp.Y >= min && gp.Y <= max {
+			in = append(in, gp)
+		}
+	}
+	if len(in) == 0 {
+		return nil
+	}
+	var c cursor
+	geom := []uint32{command(cmdMoveTo, len(in))}
+	for _, p := range in {
+		geom = c.appendPoint(geom, p)
+	}
+	return geom
+}
+
+func (e *layerEncoder) lines(lines [][]tiling.PointG) []uint32 {
+	var geom []uint32
+	var c cursor
+	for _, l := range lines {
+		for _, part := range e.grid.ClipLine(tiling.GeoToMercLine(l)) {
+			gl := e.toGrid(part)
+			if len(gl) < 2 {
+				continue
+			}
+			geom = append(geom, command(cmdMoveTo, 1))
+			geom = c.appendPoint(geom, gl[0])
+			geom = append(geom, command(cmdLineTo, len(gl)-1))
+			for _, p := range gl[1:] {
+				geom = c.appendPoint(geom, p)
+			}
+		}
+	}
+	return geom
+}
+
+func (e *layerEncoder) polygons(polys []tiling.PolygonG) []uint32 {
+	var geom []uint32
+	var c cursor
+	for _, poly := range polys {
+		for i, ring := range tiling.GeoToMercPolygon(poly) {
+			gr := e.ring(ring)
+			if gr == nil {
+				if i == 0 {
+					break
+				}
+				continue
+			}
+			if a := ringArea(gr); (i == 0) != (a > 0) {
+				for l, r := 0, len(gr)-1; l < r; l, r = l+1, r-1 {
+					gr[l], gr[r] = gr[r], gr[l]
+				}
+			}
+			geom = append(geom, command(cmdMoveTo, 1))
+			geom = c.appendPoint(geom, gr[0])
+			geom = append(geom, command(cmdLineTo, len(gr)-1))
+			for _, p := range gr[1:] {
+				geom = c.appendPoint(geom, p)
+			}
+			geom = append(geom, command(cmdClosePath, 1))
+		}
+	}
+	return geom
+}
+
+//ring clips the ring and converts it to the grid, it gives nil when no area is left
+func (e *layerEncoder) ring(ring []tiling.PointM) []tiling.MVTPoint {
+	gr := e.toGrid(e.grid.ClipRing(ring))
+	if n := len(gr); n > 1 && gr[0] == gr[n-1] {
+		gr = gr[:n-1]
+	}
+	if len(gr) < 3 || ringArea(gr) == 0 {
+		return nil
+	}
+	return gr
+}
+
+func command(id, count int) uint32 {
+	return uint32(id&7) | uint32(count)<<3
+}
+
+//cursor tracks the position of the pen to write the point parameters as deltas
+type cursor struct {
+	x, y int
+}
+
+func (c *cursor) appendPoint(geom []uint32, p tiling.MVTPoint) []uint32 {
+	geom = append(geom, uint32(zigzag(int64(p.X-c.x))), uint32(zigzag(int64(p.Y-c.y))))
+	c.x, c.y = p.X, p.Y
+	return geom
+}
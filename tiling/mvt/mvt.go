@@ -0,0 +1,70 @@
+//Package mvt encodes and decodes Mapbox Vector Tiles 2.1 https://github.com/mapbox/vector-tile-spec/tree/master/2.1
+//addressing tiles with tiling.Tile in the XYZ scheme. Geometries are given and returned in geographic coordinates.
+package mvt
+
+import "github.com/trealtamira/gopkgs/tiling"
+
+//Version is the version of the MVT specification written in the layers
+const Version = 2
+
+//GeomType is the type of the geometry of a feature
+type GeomType int
+
+//GeomType values defined by the spec
+const (
+	Unknown    GeomType = 0
+	Point      GeomType = 1
+	LineString GeomType = 2
+	Polygon    GeomType = 3
+)
+
+//Feature is a feature of a layer, only the geometry field matching Type is used.
+//Properties values can be string, bool, float32, float64 or any integer type;
+//decoded integers are int64 or uint64.
+type Feature struct {
+	ID         uint64
+	Type       GeomType
+	Points     []tiling.PointG
+	Lines      [][]tiling.PointG
+	Polygons   []tiling.PolygonG
+	Properties map[string]interface{}
+}
+
+//Layer is a named set of features. Extent is the side of the tile grid, DefaultMVTExtent if zero,
+//and Buffer the grid units kept around the tile when clipping the geometries.
+type Layer struct {
+	Name     string
+	Extent   int
+	Buffer   int
+	Features []Feature
+}
+
+//extent gives the grid extent of the layer
+func (l Layer) extent() int {
+	if l.Extent <= 0 {
+		return tiling.DefaultMVTExtent
+	}
+	return l.Extent
+}
+
+//grid gives the grid of the layer in the XYZ tile t
+func (l Layer) grid(t tiling.Tile) tiling.MVTGrid {
+	return tiling.NewZoomLevel(t.Z).MVTGrid(t.X, t.Y, l.extent(), l.Buffer)
+}
+
+//MVT command ids
+const (
+	cmdMoveTo    = 1
+	cmdLineTo    = 2
+	cmdClosePath = 7
+)
+
+//ringArea gives twice the signed area of the ring in grid coordinates, positive for exterior rings
+func ringArea(ring []tiling.MVTPoint) int64 {
+	var a int64
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		a += int64(p.X)*int64(q.Y) - int64(q.X)*int64(p.Y)
+	}
+	return a
+}
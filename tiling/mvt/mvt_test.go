@@ -0,0 +1,142 @@
+package mvt_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/mvt"
+)
+
+var tile = tiling.Tile{X: 8508, Y: 5915, Z: 14}
+
+//grid gives the geo points of the given grid coordinates of the test tile
+func grid(extent int, coords ...int) []tiling.PointG {
+	g := tiling.NewZoomLevel(tile.Z).MVTGrid(tile.X, tile.Y, extent, 0)
+	res := make([]tiling.PointG, 0, len(coords)/2)
+	for i := 0; i+1 < len(coords); i += 2 {
+		res = append(res, g.ToGeo(tiling.MVTPoint{X: coords[i], Y: coords[i+1]}))
+	}
+	return res
+}
+
+func TestEncodePoint(t *testing.T) {
+	data, err := mvt.Encode(tile, []mvt.Layer{{
+		Name:     "pts",
+		Features: []mvt.Feature{{Type: mvt.Point, Points: grid(4096, 25, 17)}},
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	feature := []byte{0x18, 0x01, 0x22, 0x03, 9, 50, 34}
+	layer := append([]byte{0x78, 0x02, 0x0A, 0x03, 'p', 't', 's', 0x12, byte(len(feature))}, feature...)
+	layer = append(layer, 0x28, 0x80, 0x20)
+	expected := append([]byte{0x1A, byte(len(layer))}, layer...)
+	if !bytes.Equal(data, expected) {
+		t.Errorf("Encoded tile is different (expected, actual)\n%v\n%v", expected, data)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	outer := grid(4096, 100, 100, 100, 3000, 3000, 3000, 3000, 100)
+	hole := grid(4096, 1000, 1000, 2000, 1000, 2000, 2000, 1000, 2000)
+	layers := []mvt.Layer{
+		{
+			Name:   "roads",
+			Buffer: 64,
+			Features: []mvt.Feature{
+				{ID: 1, Type: mvt.LineString, Lines: [][]tiling.PointG{grid(4096, 2, 2, 2, 10, 10, 10)}, Properties: map[string]interface{}{"name": "via Roma", "lanes": 2}},
+				{ID: 2, Type: mvt.LineString, Lines: [][]tiling.PointG{grid(4096, -1000, 10, 5000, 10)}, Properties: map[string]interface{}{"name": "via Roma", "oneway": true}},
+			},
+		},
+		{
+			Name:   "areas",
+			Extent: 4096,
+			Features: []mvt.Feature{
+				{Type: mvt.Polygon, Polygons: []tiling.PolygonG{{outer, hole}}, Properties: map[string]interface{}{"area": 12.5, "code": uint64(7), "delta": int64(-3), "ratio": float32(0.5)}},
+				{Type: mvt.Polygon, Polygons: []tiling.PolygonG{{grid(4096, 8000, 8000, 9000, 8000, 9000, 9000)}}},
+			},
+		},
+	}
+	data, err := mvt.Encode(tile, layers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := bytes.Count(data, []byte("via Roma")); n != 1 {
+		t.Errorf("Values are not deduplicated: %d copies", n)
+	}
+	if n := bytes.Count(data, []byte("name")); n != 1 {
+		t.Errorf("Keys are not deduplicated: %d copies", n)
+	}
+	decoded, err := mvt.Decode(tile, data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].Name != "roads" || decoded[1].Name != "areas" || decoded[0].Extent != 4096 {
+		t.Fatalf("Unexpected layers %+v", decoded)
+	}
+	roads := decoded[0].Features
+	if len(roads) != 2 || roads[0].ID != 1 || roads[0].Type != mvt.LineString {
+		t.Fatalf("Unexpected roads %+v", roads)
+	}
+	if !reflect.DeepEqual(roads[0].Properties, map[string]interface{}{"name": "via Roma", "lanes": int64(2)}) {
+		t.Errorf("Unexpected properties %+v", roads[0].Properties)
+	}
+	checkPoints(t, roads[0].Lines[0], grid(4096, 2, 2, 2, 10, 10, 10))
+	checkPoints(t, roads[1].Lines[0], grid(4096, -64, 10, 4160, 10))
+	areas := decoded[1].Features
+	if len(areas) != 1 {
+		t.Fatalf("Polygon outside the tile should be dropped: %+v", areas)
+	}
+	if !reflect.DeepEqual(areas[0].Properties, map[string]interface{}{"area": 12.5, "code": uint64(7), "delta": int64(-3), "ratio": float32(0.5)}) {
+		t.Errorf("Unexpected properties %+v", areas[0].Properties)
+	}
+	if len(areas[0].Polygons) != 1 || len(areas[0].Polygons[0]) != 2 {
+		t.Fatalf("Expected a polygon with a hole, got %+v", areas[0].Polygons)
+	}
+	checkRing(t, areas[0].Polygons[0][0], grid(4096, 100, 100, 3000, 100, 3000, 3000, 100, 3000))
+	checkRing(t, areas[0].Polygons[0][1], grid(4096, 1000, 1000, 1000, 2000, 2000, 2000, 2000, 1000))
+}
+
+func checkPoints(t *testing.T, actual, expected []tiling.PointG) {
+	t.Helper()
+	if len(actual) != len(expected) {
+		t.Errorf("Expected %d points, got %d: %v", len(expected), len(actual), actual)
+		return
+	}
+	for i := range actual {
+		if math.Abs(actual[i].Lat-expected[i].Lat) > 1e-9 || math.Abs(actual[i].Lon-expected[i].Lon) > 1e-9 {
+			t.Errorf("Point %d is different (expected, actual) %+v != %+v", i, expected[i], actual[i])
+		}
+	}
+}
+
+//checkRing compares two open rings which may start from different vertices
+func checkRing(t *testing.T, actual, expected []tiling.PointG) {
+	t.Helper()
+	for i := range actual {
+		if math.Abs(actual[i].Lat-expected[0].Lat) < 1e-9 && math.Abs(actual[i].Lon-expected[0].Lon) < 1e-9 {
+			actual = append(actual[i:len(actual):len(actual)], actual[:i]...)
+			break
+		}
+	}
+	checkPoints(t, actual, expected)
+}
+
+func TestEncodeErrors(t *testing.T) {
+	bad := [][]mvt.Layer{
+		{{Features: []mvt.Feature{{Type: mvt.Point, Points: grid(4096, 1, 1)}}}},
+		{{Name: "x", Features: []mvt.Feature{{Type: mvt.Unknown}}}},
+		{{Name: "x", Features: []mvt.Feature{{Type: mvt.Point, Points: grid(4096, 1, 1), Properties: map[string]interface{}{"k": []int{1}}}}}},
+	}
+	for i, layers := range bad {
+		if _, err := mvt.Encode(tile, layers); err == nil {
+			t.Errorf("Layers %d should fail", i)
+		}
+	}
+	if _, err := mvt.Decode(tile, []byte{0x1A, 0x10, 0x78}); err == nil {
+		t.Errorf("A truncated tile should fail")
+	}
+}
@@ -0,0 +1,133 @@
+package mvt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+//protobuf wire types used by the MVT messages
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("Truncated protobuf message")
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendKey(b []byte, field, wire int) []byte {
+	return appendVarint(b, uint64(field<<3|wire))
+}
+
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	return appendVarint(appendKey(b, field, wireVarint), v)
+}
+
+func appendBytesField(b []byte, field int, data []byte) []byte {
+	b = appendVarint(appendKey(b, field, wireBytes), uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendPackedField(b []byte, field int, vs []uint32) []byte {
+	var data []byte
+	for _, v := range vs {
+		data = appendVarint(data, uint64(v))
+	}
+	return appendBytesField(b, field, data)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+//field is a decoded protobuf field: varint and fixed values are kept in val, length delimited ones in data
+type field struct {
+	num  int
+	wire int
+	val  uint64
+	data []byte
+}
+
+//eachField calls fn for every field of the message until fn returns an error
+func eachField(msg []byte, fn func(field) error) error {
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return errTruncated
+		}
+		msg = msg[n:]
+		f := field{num: int(key >> 3), wire: int(key & 7)}
+		switch f.wire {
+		case wireVarint:
+			f.val, n = binary.Uvarint(msg)
+			if n <= 0 {
+				return errTruncated
+			}
+			msg = msg[n:]
+		case wireFixed64:
+			if len(msg) < 8 {
+				return errTruncated
+			}
+			f.val = binary.LittleEndian.Uint64(msg)
+			msg = msg[8:]
+		case wireFixed32:
+			if len(msg) < 4 {
+				return errTruncated
+			}
+			f.val = uint64(binary.LittleEndian.Uint32(msg))
+			msg = msg[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < size {
+				return errTruncated
+			}
+			f.data = msg[n : n+int(size)]
+			msg = msg[n+int(size):]
+		default:
+			return fmt.Errorf("Unsupported protobuf wire type %d", f.wire)
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//uint32s reads a repeated uint32 field, either packed or not
+func (f field) uint32s(dst []uint32) ([]uint32, error) {
+	if f.wire == wireVarint {
+		return append(dst, uint32(f.val)), nil
+	}
+	data := f.data
+	for len(data) > 0 {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		dst = append(dst, uint32(v))
+		data = data[n:]
+	}
+	return dst, nil
+}
+
+func (f field) float32() float32 {
+	return math.Float32frombits(uint32(f.val))
+}
+
+func (f field) float64() float64 {
+	return math.Float64frombits(f.val)
+}
@@ -0,0 +1,95 @@
+package tiling_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestMVTGridTransform(t *testing.T) {
+	z := tiling.NewZoomLevel(12)
+	g := z.MVTGrid(2200, 1500, tiling.DefaultMVTExtent, tiling.DefaultMVTBuffer)
+	ext := g.TileExtent()
+	if !tiling.Equals(ext, z.ExtentOfTile(2200, 1500)) {
+		t.Errorf("Unexpected tile extent %+v", ext)
+	}
+	corners := []tiling.PointM{ext.UL(), ext.LR(), {E: (ext.West + ext.East) / 2, N: (ext.North + ext.South) / 2}}
+	points := []tiling.MVTPoint{{X: 0, Y: 0}, {X: 4096, Y: 4096}, {X: 2048, Y: 2048}}
+	for i, c := range corners {
+		if p := g.FromMerc(c); p != points[i] {
+			t.Errorf("Grid point is different (expected, actual) %+v != %+v", points[i], p)
+		}
+		if m := g.ToMerc(points[i]); math.Abs(m.E-c.E) > 1e-6 || math.Abs(m.N-c.N) > 1e-6 {
+			t.Errorf("Mercator point is different (expected, actual) %+v != %+v", c, m)
+		}
+	}
+	geo := tiling.PointG{Lat: 45.1234, Lon: 13.4321}
+	gz := tiling.NewZoomLevel(14)
+	tl, _ := gz.TileOfGeo(geo)
+	gg := gz.MVTGrid(tl.X, tl.Y, tiling.DefaultMVTExtent, 0)
+	p := gg.FromGeo(geo)
+	if p.X < 0 || p.X > 4096 || p.Y < 0 || p.Y > 4096 {
+		t.Errorf("Point %+v is outside its tile", p)
+	}
+	back := gg.ToGeo(p)
+	if d := math.Hypot(back.Lat-geo.Lat, back.Lon-geo.Lon); d > 1e-5 {
+		t.Errorf("Round trip moved the point by %g degrees: %+v", d, back)
+	}
+	out := g.FromMerc(tiling.PointM{E: g.BufferedExtent().West, N: ext.North})
+	if out.X != -64 || out.Y != 0 {
+		t.Errorf("Buffer point is different (expected, actual) {-64 0} != %+v", out)
+	}
+	tms := tiling.NewZoomLevel(12).WithScheme(tiling.TMS).MVTGrid(2200, tiling.FlipY(1500, 12), 4096, 0)
+	if !tiling.Equals(tms.TileExtent(), ext) {
+		t.Errorf("TMS grid does not honour the scheme: %+v", tms.TileExtent())
+	}
+}
+
+func TestMVTGridClipLine(t *testing.T) {
+	g := tiling.NewZoomLevel(4).MVTGrid(8, 5, 4096, 256)
+	pt := func(x, y int) tiling.PointM { return g.ToMerc(tiling.MVTPoint{X: x, Y: y}) }
+	line := []tiling.PointM{pt(-1000, 1000), pt(1000, 1000), pt(1000, 5000), pt(2000, 5000), pt(2000, 2000), pt(2000, 1000)}
+	parts := g.ClipLine(line)
+	expected := [][]tiling.MVTPoint{{{X: -256, Y: 1000}, {X: 1000, Y: 1000}, {X: 1000, Y: 4352}}, {{X: 2000, Y: 4352}, {X: 2000, Y: 2000}, {X: 2000, Y: 1000}}}
+	if len(parts) != len(expected) {
+		t.Fatalf("Expected %d parts, got %d", len(expected), len(parts))
+	}
+	for i, part := range parts {
+		if len(part) != len(expected[i]) {
+			t.Fatalf("Part %d has %d points instead of %d", i, len(part), len(expected[i]))
+		}
+		for j, p := range part {
+			if gp := g.FromMerc(p); gp != expected[i][j] {
+				t.Errorf("Point %d of part %d is different (expected, actual) %+v != %+v", j, i, expected[i][j], gp)
+			}
+		}
+	}
+	if parts := g.ClipLine([]tiling.PointM{pt(-5000, 0), pt(-5000, 4000)}); len(parts) != 0 {
+		t.Errorf("A line outside the tile should be dropped: %v", parts)
+	}
+}
+
+func TestMVTGridClipRing(t *testing.T) {
+	g := tiling.NewZoomLevel(4).MVTGrid(8, 5, 4096, 64)
+	pt := func(x, y int) tiling.PointM { return g.ToMerc(tiling.MVTPoint{X: x, Y: y}) }
+	ring := []tiling.PointM{pt(-1000, -1000), pt(2000, -1000), pt(2000, 2000), pt(-1000, 2000), pt(-1000, -1000)}
+	clipped := g.ClipRing(ring)
+	expected := map[tiling.MVTPoint]bool{{X: -64, Y: -64}: true, {X: 2000, Y: -64}: true, {X: 2000, Y: 2000}: true, {X: -64, Y: 2000}: true}
+	if len(clipped) != 4 {
+		t.Fatalf("Expected 4 vertices, got %d", len(clipped))
+	}
+	for _, p := range clipped {
+		if gp := g.FromMerc(p); !expected[gp] {
+			t.Errorf("Unexpected vertex %+v", gp)
+		}
+	}
+	inside := []tiling.PointM{pt(10, 10), pt(100, 10), pt(10, 100)}
+	if clipped := g.ClipRing(inside); len(clipped) != 3 {
+		t.Errorf("A ring inside the tile should be unchanged: %v", clipped)
+	}
+	outside := []tiling.PointM{pt(5000, 10), pt(6000, 10), pt(5000, 100)}
+	if clipped := g.ClipRing(outside); clipped != nil {
+		t.Errorf("A ring outside the tile should be dropped: %v", clipped)
+	}
+}
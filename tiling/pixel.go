@@ -0,0 +1,85 @@
+package tiling
+
+import "math"
+
+//standardPixelSize is the OGC standardized rendering pixel size in meters (0.28 mm)
+const standardPixelSize = 0.00028
+
+//Pixel is a point in pixel coordinates: X grows eastward and Y southward.
+//Global pixels have origin in the upper left corner of the zoom level,
+//tile pixels have origin in the upper left corner of their tile whatever the scheme.
+type Pixel struct {
+	X float64
+	Y float64
+}
+
+//WithTileSize gives a copy of the zoom level whose tiles are size pixels wide,
+//a new zoom level, or a size not greater than 0, uses DefaultTileSize
+func (z *ZoomLevel) WithTileSize(size int) *ZoomLevel {
+	if size <= 0 {
+		size = DefaultTileSize
+	}
+	zl := *z
+	zl.size = size
+	return &zl
+}
+
+//TileSize returns the side in pixels of the tiles of the zoom level
+func (z *ZoomLevel) TileSize() int {
+	return z.size
+}
+
+//Resolution gives the size in mercator meters of a pixel of the zoom level
+func (z *ZoomLevel) Resolution() float64 {
+	return z.hLength / float64(z.size)
+}
+
+//GroundResolution gives the size on the ground, in meters, of a pixel of the zoom level at the given latitude
+func (z *ZoomLevel) GroundResolution(lat float64) float64 {
+	return z.Resolution() * math.Cos(lat*deg2rad)
+}
+
+//ScaleDenominator gives the map scale denominator of the zoom level at the equator,
+//for the OGC standardized rendering pixel size of 0.28 mm
+func (z *ZoomLevel) ScaleDenominator() float64 {
+	return z.Resolution() / standardPixelSize
+}
+
+//MercToPixel gives the global pixel coordinates of the given mercator point
+func (z *ZoomLevel) MercToPixel(m PointM) Pixel {
+	res := z.Resolution()
+	return Pixel{X: (m.E + equator/2) / res, Y: (meridian - m.N) / res}
+}
+
+//PixelToMerc gives the mercator point of the given global pixel coordinates
+func (z *ZoomLevel) PixelToMerc(p Pixel) PointM {
+	res := z.Resolution()
+	return PointM{E: p.X*res - equator/2, N: meridian - p.Y*res}
+}
+
+//GeoToPixel gives the global pixel coordinates of the given geo point
+func (z *ZoomLevel) GeoToPixel(g PointG) Pixel {
+	return z.MercToPixel(GeoToMerc(g))
+}
+
+//PixelToGeo gives the geo point of the given global pixel coordinates
+func (z *ZoomLevel) PixelToGeo(p Pixel) PointG {
+	return MercToGeo(z.PixelToMerc(p))
+}
+
+//TileOfPixel gives the tile containing the given global pixel and the pixel offset inside that tile
+func (z *ZoomLevel) TileOfPixel(p Pixel) (Tile, Pixel) {
+	size := float64(z.size)
+	x, y := math.Floor(p.X/size), math.Floor(p.Y/size)
+	t := Tile{X: int(x), Y: int(y), Z: z.zoom}
+	return ConvertTile(t, XYZ, z.scheme), Pixel{X: p.X - x*size, Y: p.Y - y*size}
+}
+
+//PixelOfTile gives the global pixel coordinates of the given offset inside the tile (x, y)
+func (z *ZoomLevel) PixelOfTile(x, y int, offset Pixel) Pixel {
+	if !z.scheme.topLeft() {
+		y = FlipY(y, z.zoom)
+	}
+	size := float64(z.size)
+	return Pixel{X: float64(x)*size + offset.X, Y: float64(y)*size + offset.Y}
+}
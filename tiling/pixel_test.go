@@ -0,0 +1,106 @@
+package tiling_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestResolution(t *testing.T) {
+	zooms := []int{0, 0, 5, 18}
+	sizes := []int{256, 512, 256, 256}
+	res := []float64{156543.03392804097, 78271.51696402048, 4891.969810251280, 0.5971642834779395}
+	scales := []float64{559082264.0287178, 279541132.0143589, 17471320.75089743, 2132.729583849784}
+	for i, z := range zooms {
+		t.Run(fmt.Sprintf("Zoom %d size %d", z, sizes[i]), func(t *testing.T) {
+			zl := tiling.NewZoomLevel(z).WithTileSize(sizes[i])
+			if zl.TileSize() != sizes[i] {
+				t.Errorf("Tile size is different (expected, actual) %d != %d", sizes[i], zl.TileSize())
+			}
+			if r := zl.Resolution(); math.Abs(r-res[i]) > 0.0000001 {
+				t.Errorf("Resolution is different (expected, actual) %f != %f", res[i], r)
+			}
+			if s := zl.ScaleDenominator(); math.Abs(s-scales[i]) > 0.0001 {
+				t.Errorf("Scale denominator is different (expected, actual) %f != %f", scales[i], s)
+			}
+			if g := zl.GroundResolution(60); math.Abs(g-res[i]/2) > 0.0000001 {
+				t.Errorf("Ground resolution at 60° is different (expected, actual) %f != %f", res[i]/2, g)
+			}
+		})
+	}
+	if tiling.NewZoomLevel(3).TileSize() != tiling.DefaultTileSize {
+		t.Errorf("New zoom levels should use the default tile size")
+	}
+	for _, size := range []int{0, -256} {
+		zl := tiling.NewZoomLevel(3).WithTileSize(size)
+		if zl.TileSize() != tiling.DefaultTileSize {
+			t.Errorf("Tile size %d should fall back to the default, got %d", size, zl.TileSize())
+		}
+		if r := zl.Resolution(); math.IsInf(r, 0) || math.IsNaN(r) {
+			t.Errorf("Resolution with tile size %d is not finite: %f", size, r)
+		}
+	}
+}
+
+func TestPixelConversions(t *testing.T) {
+	mercs := []tiling.PointM{
+		tiling.PointM{E: 0, N: 0},
+		tiling.PointM{E: 910763.1357121654, N: 5309377.085697312},
+		tiling.PointM{E: 12665509.838740565, N: -3025789.0757535584},
+	}
+	zooms := []int{0, 6, 17}
+	sizes := []int{256, 512, 256}
+	pixels := []tiling.Pixel{
+		tiling.Pixel{X: 128, Y: 128},
+		tiling.Pixel{X: 17128.700536625252, Y: 12042.700039749761},
+		tiling.Pixel{X: 27381927.458106197, Y: 19310680.541893803},
+	}
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 33, Y: 23, Z: 6},
+		tiling.Tile{X: 106960, Y: 75432, Z: 17},
+	}
+	for i, m := range mercs {
+		t.Run(fmt.Sprintf("Point %d (E,N)(%f, %f)", i, m.E, m.N), func(t *testing.T) {
+			zl := tiling.NewZoomLevel(zooms[i]).WithTileSize(sizes[i])
+			p := zl.MercToPixel(m)
+			if math.Abs(p.X-pixels[i].X) > 0.01 || math.Abs(p.Y-pixels[i].Y) > 0.01 {
+				t.Errorf("Pixel is different (expected, actual) %+v != %+v", pixels[i], p)
+			}
+			back := zl.PixelToMerc(p)
+			if math.Abs(back.E-m.E) > 0.0001 || math.Abs(back.N-m.N) > 0.0001 {
+				t.Errorf("Back conversion is different (expected, actual) %+v != %+v", m, back)
+			}
+			tl, off := zl.TileOfPixel(p)
+			if tl != tiles[i] || tl != zl.TileOfMerc(m) {
+				t.Errorf("Tile is different (expected, actual) %+v != %+v", tiles[i], tl)
+			}
+			if off.X < 0 || off.Y < 0 || off.X >= float64(sizes[i]) || off.Y >= float64(sizes[i]) {
+				t.Errorf("Offset %+v is outside the tile", off)
+			}
+			if g := zl.PixelOfTile(tl.X, tl.Y, off); math.Abs(g.X-p.X) > 0.0000001 || math.Abs(g.Y-p.Y) > 0.0000001 {
+				t.Errorf("Global pixel is different (expected, actual) %+v != %+v", p, g)
+			}
+			geo := zl.PixelToGeo(p)
+			if gp := zl.GeoToPixel(geo); math.Abs(gp.X-p.X) > 0.0001 || math.Abs(gp.Y-p.Y) > 0.0001 {
+				t.Errorf("Geo round trip is different (expected, actual) %+v != %+v", p, gp)
+			}
+		})
+	}
+}
+
+func TestTilePixelScheme(t *testing.T) {
+	zl := tiling.NewZoomLevel(6).WithScheme(tiling.TMS)
+	ext := zl.ExtentOfTile(33, 40)
+	ul := zl.PixelOfTile(33, 40, tiling.Pixel{X: 0, Y: 0})
+	m := zl.PixelToMerc(ul)
+	if math.Abs(m.E-ext.West) > 0.0001 || math.Abs(m.N-ext.North) > 0.0001 {
+		t.Errorf("Upper left pixel is not the upper left vertex (expected, actual) %+v != %+v", ext.UL(), m)
+	}
+	tl, _ := zl.TileOfPixel(tiling.Pixel{X: ul.X + 10, Y: ul.Y + 10})
+	if tl != (tiling.Tile{X: 33, Y: 40, Z: 6}) {
+		t.Errorf("Tile of pixel is not in the TMS scheme: %+v", tl)
+	}
+}
@@ -0,0 +1,203 @@
+package pmtiles
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+)
+
+//entry is a directory entry: a run of RunLength tiles with the same content, or a leaf directory when RunLength is 0
+type entry struct {
+	TileID    uint64
+	Offset    uint64
+	Length    uint32
+	RunLength uint32
+}
+
+//serializeDirectory encodes the entries, sorted by tile id, with the given compression
+func serializeDirectory(entries []entry, c Compression) ([]byte, error) {
+	var buf []byte
+	tmp := make([]byte, binary.MaxVarintLen64)
+	put := func(v uint64) {
+		n := binary.PutUvarint(tmp, v)
+		buf = append(buf, tmp[:n]...)
+	}
+	put(uint64(len(entries)))
+	var last uint64
+	for _, e := range entries {
+		put(e.TileID - last)
+		last = e.TileID
+	}
+	for _, e := range entries {
+		put(uint64(e.RunLength))
+	}
+	for _, e := range entries {
+		put(uint64(e.Length))
+	}
+	for i, e := range entries {
+		if i > 0 && e.Offset == entries[i-1].Offset+uint64(entries[i-1].Length) {
+			put(0)
+		} else {
+			put(e.Offset + 1)
+		}
+	}
+	return compress(buf, c)
+}
+
+//deserializeDirectory decodes the entries of a directory with the given compression
+func deserializeDirectory(data []byte, c Compression) ([]entry, error) {
+	raw, err := decompress(data, c)
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(raw)
+	read := func() (uint64, error) {
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return 0, fmt.Errorf("Corrupted directory: %v", err)
+		}
+		return v, nil
+	}
+	n, err := read()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(raw)) {
+		return nil, fmt.Errorf("Corrupted directory: %d entries in %d bytes", n, len(raw))
+	}
+	entries := make([]entry, n)
+	var last uint64
+	for i := range entries {
+		v, err := read()
+		if err != nil {
+			return nil, err
+		}
+		last += v
+		entries[i].TileID = last
+	}
+	for i := range entries {
+		v, err := read()
+		if err != nil {
+			return nil, err
+		}
+		entries[i].RunLength = uint32(v)
+	}
+	for i := range entries {
+		v, err := read()
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Length = uint32(v)
+	}
+	for i := range entries {
+		v, err := read()
+		if err != nil {
+			return nil, err
+		}
+		if v == 0 && i > 0 {
+			entries[i].Offset = entries[i-1].Offset + uint64(entries[i-1].Length)
+		} else if v == 0 {
+			return nil, fmt.Errorf("Corrupted directory: first entry without offset")
+		} else {
+			entries[i].Offset = v - 1
+		}
+	}
+	return entries, nil
+}
+
+//findEntry gives the entry with the greatest tile id not greater than id, if any
+func findEntry(entries []entry, id uint64) (entry, bool) {
+	lo, hi := 0, len(entries)-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		switch {
+		case entries[mid].TileID < id:
+			lo = mid + 1
+		case entries[mid].TileID > id:
+			hi = mid - 1
+		default:
+			return entries[mid], true
+		}
+	}
+	if hi >= 0 {
+		e := entries[hi]
+		if e.RunLength == 0 || id-e.TileID < uint64(e.RunLength) {
+			return e, true
+		}
+	}
+	return entry{}, false
+}
+
+//buildDirectories lays out the root directory and, when it does not fit in the first 16 KiB,
+//the leaf directories it points to
+func buildDirectories(entries []entry, c Compression) (root, leaves []byte, err error) {
+	root, err = serializeDirectory(entries, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(root) <= rootSize-HeaderSize {
+		return root, nil, nil
+	}
+	for leafSize := 4096; ; leafSize *= 2 {
+		var rootEntries []entry
+		leaves = leaves[:0]
+		for i := 0; i < len(entries); i += leafSize {
+			end := i + leafSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			leaf, err := serializeDirectory(entries[i:end], c)
+			if err != nil {
+				return nil, nil, err
+			}
+			rootEntries = append(rootEntries, entry{TileID: entries[i].TileID, Offset: uint64(len(leaves)), Length: uint32(len(leaf))})
+			leaves = append(leaves, leaf...)
+		}
+		root, err = serializeDirectory(rootEntries, c)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(root) <= rootSize-HeaderSize {
+			return root, leaves, nil
+		}
+	}
+}
+
+//compress encodes data with the given internal compression
+func compress(data []byte, c Compression) ([]byte, error) {
+	switch c {
+	case NoCompression:
+		return data, nil
+	case Gzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("Unsupported internal compression %d", c)
+	}
+}
+
+//decompress decodes data with the given internal compression
+func decompress(data []byte, c Compression) ([]byte, error) {
+	switch c {
+	case NoCompression:
+		return data, nil
+	case Gzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("Corrupted gzip data: %v", err)
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	default:
+		return nil, fmt.Errorf("Unsupported internal compression %d", c)
+	}
+}
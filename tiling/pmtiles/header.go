@@ -0,0 +1,145 @@
+//Package pmtiles reads and writes PMTiles version 3 archives https://github.com/protomaps/PMTiles/blob/main/spec/v3/spec.md
+//addressing tiles with tiling.Tile in the XYZ scheme.
+package pmtiles
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//HeaderSize is the size in bytes of the PMTiles v3 header
+const HeaderSize = 127
+
+//rootSize is the maximum size in bytes of the header and the root directory together
+const rootSize = 16384
+
+const magic = "PMTiles"
+
+//Compression is the compression of the tiles or of the directories and metadata
+type Compression uint8
+
+//Compression values defined by the spec
+const (
+	UnknownCompression Compression = 0
+	NoCompression      Compression = 1
+	Gzip               Compression = 2
+	Brotli             Compression = 3
+	Zstd               Compression = 4
+)
+
+//TileType is the format of the tiles of the archive
+type TileType uint8
+
+//TileType values defined by the spec
+const (
+	UnknownType TileType = 0
+	MVT         TileType = 1
+	PNG         TileType = 2
+	JPEG        TileType = 3
+	WEBP        TileType = 4
+	AVIF        TileType = 5
+)
+
+//Header is the fixed size header at the start of an archive
+type Header struct {
+	RootOffset          uint64
+	RootLength          uint64
+	MetadataOffset      uint64
+	MetadataLength      uint64
+	LeafOffset          uint64
+	LeafLength          uint64
+	TileDataOffset      uint64
+	TileDataLength      uint64
+	AddressedTiles      uint64
+	TileEntries         uint64
+	TileContents        uint64
+	Clustered           bool
+	InternalCompression Compression
+	TileCompression     Compression
+	TileType            TileType
+	MinZoom             int
+	MaxZoom             int
+	Bounds              tiling.ExtentG
+	CenterZoom          int
+	Center              tiling.PointG
+}
+
+//e7 converts degrees to the fixed point integers of the header
+func e7(v float64) uint32 {
+	return uint32(int32(math.Round(v * 1e7)))
+}
+
+//fromE7 converts the fixed point integers of the header to degrees
+func fromE7(v uint32) float64 {
+	return float64(int32(v)) / 1e7
+}
+
+//marshal encodes the header in its binary form
+func (h *Header) marshal() []byte {
+	b := make([]byte, HeaderSize)
+	copy(b, magic)
+	b[7] = 3
+	le := binary.LittleEndian
+	for i, v := range []uint64{
+		h.RootOffset, h.RootLength, h.MetadataOffset, h.MetadataLength, h.LeafOffset, h.LeafLength,
+		h.TileDataOffset, h.TileDataLength, h.AddressedTiles, h.TileEntries, h.TileContents,
+	} {
+		le.PutUint64(b[8+8*i:], v)
+	}
+	if h.Clustered {
+		b[96] = 1
+	}
+	b[97] = byte(h.InternalCompression)
+	b[98] = byte(h.TileCompression)
+	b[99] = byte(h.TileType)
+	b[100] = byte(h.MinZoom)
+	b[101] = byte(h.MaxZoom)
+	le.PutUint32(b[102:], e7(h.Bounds.MinLon))
+	le.PutUint32(b[106:], e7(h.Bounds.MinLat))
+	le.PutUint32(b[110:], e7(h.Bounds.MaxLon))
+	le.PutUint32(b[114:], e7(h.Bounds.MaxLat))
+	b[118] = byte(h.CenterZoom)
+	le.PutUint32(b[119:], e7(h.Center.Lon))
+	le.PutUint32(b[123:], e7(h.Center.Lat))
+	return b
+}
+
+//unmarshalHeader decodes the binary form of the header
+func unmarshalHeader(b []byte) (Header, error) {
+	var h Header
+	if len(b) < HeaderSize {
+		return h, fmt.Errorf("Header too short: %d bytes", len(b))
+	}
+	if string(b[:7]) != magic {
+		return h, fmt.Errorf("Not a PMTiles archive")
+	}
+	if b[7] != 3 {
+		return h, fmt.Errorf("Unsupported PMTiles version %d", b[7])
+	}
+	le := binary.LittleEndian
+	fields := []*uint64{
+		&h.RootOffset, &h.RootLength, &h.MetadataOffset, &h.MetadataLength, &h.LeafOffset, &h.LeafLength,
+		&h.TileDataOffset, &h.TileDataLength, &h.AddressedTiles, &h.TileEntries, &h.TileContents,
+	}
+	for i, f := range fields {
+		*f = le.Uint64(b[8+8*i:])
+	}
+	h.Clustered = b[96] == 1
+	h.InternalCompression = Compression(b[97])
+	h.TileCompression = Compression(b[98])
+	h.TileType = TileType(b[99])
+	h.MinZoom = int(b[100])
+	h.MaxZoom = int(b[101])
+	h.Bounds = tiling.ExtentG{
+		MinLon: fromE7(le.Uint32(b[102:])),
+		MinLat: fromE7(le.Uint32(b[106:])),
+		MaxLon: fromE7(le.Uint32(b[110:])),
+		MaxLat: fromE7(le.Uint32(b[114:])),
+	}
+	h.CenterZoom = int(b[118])
+	h.Center = tiling.PointG{Lon: fromE7(le.Uint32(b[119:])), Lat: fromE7(le.Uint32(b[123:]))}
+	return h, nil
+}
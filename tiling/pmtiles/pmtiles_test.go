@@ -0,0 +1,154 @@
+package pmtiles_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/pmtiles"
+)
+
+func TestWriteRead(t *testing.T) {
+	w := pmtiles.NewWriter(pmtiles.WriterOptions{
+		TileType:        pmtiles.PNG,
+		TileCompression: pmtiles.NoCompression,
+		Metadata:        map[string]interface{}{"name": "test"},
+	})
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 1},
+		tiling.Tile{X: 0, Y: 1, Z: 1},
+		tiling.Tile{X: 1, Y: 1, Z: 1},
+		tiling.Tile{X: 33, Y: 23, Z: 6},
+		tiling.Tile{X: 34, Y: 23, Z: 6},
+	}
+	blobs := [][]byte{[]byte("ocean"), []byte("ocean"), []byte("ocean"), []byte("land"), []byte("ocean")}
+	for i, tl := range tiles {
+		if err := w.WriteTile(tl, blobs[i]); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	var buf bytes.Buffer
+	h, err := w.Finalize(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if h.AddressedTiles != 5 || h.TileEntries != 3 || h.TileContents != 2 || h.MinZoom != 1 || h.MaxZoom != 6 || !h.Clustered {
+		t.Errorf("Unexpected header %+v", h)
+	}
+	if math.Abs(h.Bounds.MinLon+180) > 0.0000001 || math.Abs(h.Bounds.MaxLat-85.0511287) > 0.0000001 {
+		t.Errorf("Bounds are not the extent of the tiles: %+v", h.Bounds)
+	}
+
+	rd, err := pmtiles.NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rd.Header() != h {
+		t.Errorf("Read header is different (expected, actual) %+v != %+v", h, rd.Header())
+	}
+	if b, min, max, ok := rd.Describe(); !ok || b != h.Bounds || min != 1 || max != 6 {
+		t.Errorf("Description is different (expected, actual) %+v 1-6 != %+v %d-%d %v", h.Bounds, b, min, max, ok)
+	}
+	for i, tl := range tiles {
+		data, err := rd.ReadTile(tl)
+		if err != nil || !bytes.Equal(data, blobs[i]) {
+			t.Errorf("Tile %+v is different (expected, actual) %q != %q %v", tl, blobs[i], data, err)
+		}
+	}
+	missing := []tiling.Tile{
+		tiling.Tile{X: 1, Y: 0, Z: 1},
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 35, Y: 23, Z: 6},
+		tiling.Tile{X: 0, Y: 0, Z: 7},
+	}
+	for _, tl := range missing {
+		if _, err := rd.ReadTile(tl); err != pmtiles.ErrTileNotFound {
+			t.Errorf("Tile %+v should be missing, got %v", tl, err)
+		}
+	}
+	md, err := rd.Metadata()
+	if err != nil || md["name"] != "test" {
+		t.Errorf("Metadata is different: %+v %v", md, err)
+	}
+}
+
+func TestLeafDirectories(t *testing.T) {
+	const z = 8
+	w := pmtiles.NewWriter(pmtiles.WriterOptions{
+		TileType:            pmtiles.MVT,
+		InternalCompression: pmtiles.NoCompression,
+		Bounds:              tiling.ExtentG{MinLon: -180, MinLat: -85, MaxLon: 180, MaxLat: 85},
+		Center:              tiling.PointG{Lat: 45, Lon: 9},
+		CenterZoom:          4,
+	})
+	r := tiling.Range{MinX: 0, MaxX: 1<<z - 1, MinY: 0, MaxY: 1<<z - 1, ZL: z}
+	content := func(tl tiling.Tile) []byte {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(tl.X<<16|tl.Y))
+		return b
+	}
+	r.Each(func(tl tiling.Tile) bool {
+		if err := w.WriteTile(tl, content(tl)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return true
+	})
+
+	dir, err := ioutil.TempDir("", "pmtiles")
+	if err != nil {
+		t.Fatalf("Cannot create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.pmtiles")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h, err := w.Finalize(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if h.LeafLength == 0 || h.RootLength > 16384-pmtiles.HeaderSize {
+		t.Errorf("Expected leaf directories, got header %+v", h)
+	}
+	if h.Center.Lat != 45 || h.CenterZoom != 4 {
+		t.Errorf("Center is different: %+v", h)
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer f.Close()
+	rd, err := pmtiles.NewReader(f)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, tl := range []tiling.Tile{{X: 0, Y: 0, Z: z}, {X: 255, Y: 0, Z: z}, {X: 100, Y: 200, Z: z}, {X: 255, Y: 255, Z: z}} {
+		t.Run(fmt.Sprintf("Tile (X,Y,Z)(%d, %d, %d)", tl.X, tl.Y, tl.Z), func(t *testing.T) {
+			data, err := rd.ReadTile(tl)
+			if err != nil || !bytes.Equal(data, content(tl)) {
+				t.Errorf("Tile is different (expected, actual) %v != %v %v", content(tl), data, err)
+			}
+		})
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	if _, err := pmtiles.NewReader(bytes.NewReader([]byte("not an archive"))); err == nil {
+		t.Errorf("Short input should fail")
+	}
+	if _, err := pmtiles.NewReader(bytes.NewReader(make([]byte, 200))); err == nil {
+		t.Errorf("Input without magic should fail")
+	}
+	if _, err := pmtiles.NewWriter(pmtiles.WriterOptions{}).Finalize(&bytes.Buffer{}); err == nil {
+		t.Errorf("Empty archive should fail")
+	}
+}
@@ -0,0 +1,115 @@
+package pmtiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//ErrTileNotFound is returned when reading a tile missing from the archive
+var ErrTileNotFound = errors.New("Tile not found")
+
+//maxDepth is the maximum number of directories visited to find a tile
+const maxDepth = 4
+
+//Reader reads tiles from a PMTiles archive
+type Reader struct {
+	r      io.ReaderAt
+	header Header
+	root   []entry
+}
+
+//NewReader reads the header and the root directory of the archive
+func NewReader(r io.ReaderAt) (*Reader, error) {
+	buf := make([]byte, HeaderSize)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		return nil, fmt.Errorf("Cannot read header: %v", err)
+	}
+	h, err := unmarshalHeader(buf)
+	if err != nil {
+		return nil, err
+	}
+	rd := &Reader{r: r, header: h}
+	if rd.root, err = rd.directory(h.RootOffset, h.RootLength); err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
+
+//Header returns the header of the archive
+func (rd *Reader) Header() Header {
+	return rd.header
+}
+
+//Describe gives the bounds and the zoom range of the header, ok is false when the header has no bounds.
+//It implements store.Describer for stores backed by the archive.
+func (rd *Reader) Describe() (tiling.ExtentG, int, int, bool) {
+	h := rd.header
+	if h.Bounds == (tiling.ExtentG{}) || h.MinZoom < 0 || h.MaxZoom < h.MinZoom {
+		return tiling.ExtentG{}, 0, 0, false
+	}
+	return h.Bounds, h.MinZoom, h.MaxZoom, true
+}
+
+//Metadata decodes the JSON metadata of the archive
+func (rd *Reader) Metadata() (map[string]interface{}, error) {
+	raw, err := rd.section(rd.header.MetadataOffset, rd.header.MetadataLength)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decompress(raw, rd.header.InternalCompression)
+	if err != nil {
+		return nil, err
+	}
+	md := map[string]interface{}{}
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("Invalid metadata: %v", err)
+	}
+	return md, nil
+}
+
+//ReadTile gives the data of the tile, as stored in the archive, or ErrTileNotFound
+func (rd *Reader) ReadTile(t tiling.Tile) ([]byte, error) {
+	if t.Z < rd.header.MinZoom || t.Z > rd.header.MaxZoom {
+		return nil, ErrTileNotFound
+	}
+	id, err := TileID(t)
+	if err != nil {
+		return nil, err
+	}
+	entries := rd.root
+	for depth := 0; depth < maxDepth; depth++ {
+		e, ok := findEntry(entries, id)
+		if !ok {
+			return nil, ErrTileNotFound
+		}
+		if e.RunLength > 0 {
+			return rd.section(rd.header.TileDataOffset+e.Offset, uint64(e.Length))
+		}
+		if entries, err = rd.directory(rd.header.LeafOffset+e.Offset, uint64(e.Length)); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("Too many nested directories looking for tile (x, y, z)(%d, %d, %d)", t.X, t.Y, t.Z)
+}
+
+//directory reads and decodes the directory at the given position
+func (rd *Reader) directory(offset, length uint64) ([]entry, error) {
+	raw, err := rd.section(offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return deserializeDirectory(raw, rd.header.InternalCompression)
+}
+
+//section reads length bytes at offset
+func (rd *Reader) section(offset, length uint64) ([]byte, error) {
+	buf := make([]byte, length)
+	if _, err := rd.r.ReadAt(buf, int64(offset)); err != nil {
+		return nil, fmt.Errorf("Cannot read %d bytes at %d: %v", length, offset, err)
+	}
+	return buf, nil
+}
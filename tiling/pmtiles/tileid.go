@@ -0,0 +1,14 @@
+package pmtiles
+
+import "github.com/trealtamira/gopkgs/tiling"
+
+//TileID gives the PMTiles id of the tile: the position of the tile along the Hilbert curve
+//of its zoom level, counted after all the tiles of the coarser zoom levels, see tiling.Tile.HilbertID
+func TileID(t tiling.Tile) (uint64, error) {
+	return t.HilbertID()
+}
+
+//TileOfID gives the tile with the given PMTiles id, see tiling.TileOfHilbertID
+func TileOfID(id uint64) (tiling.Tile, error) {
+	return tiling.TileOfHilbertID(id)
+}
@@ -0,0 +1,57 @@
+package pmtiles_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/pmtiles"
+)
+
+func TestTileID(t *testing.T) {
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 0, Y: 0, Z: 1},
+		tiling.Tile{X: 0, Y: 1, Z: 1},
+		tiling.Tile{X: 1, Y: 1, Z: 1},
+		tiling.Tile{X: 1, Y: 0, Z: 1},
+		tiling.Tile{X: 0, Y: 0, Z: 2},
+		tiling.Tile{X: 3423, Y: 1763, Z: 12},
+	}
+	ids := []uint64{0, 1, 2, 3, 4, 5, 19078479}
+	for i, tl := range tiles {
+		t.Run(fmt.Sprintf("Tile %d (X,Y,Z)(%d, %d, %d)", i, tl.X, tl.Y, tl.Z), func(t *testing.T) {
+			id, err := pmtiles.TileID(tl)
+			if err != nil || id != ids[i] {
+				t.Errorf("Tile id is different (expected, actual) %d != %d %v", ids[i], id, err)
+			}
+			back, err := pmtiles.TileOfID(id)
+			if err != nil || back != tl {
+				t.Errorf("Tile is different (expected, actual) %+v != %+v %v", tl, back, err)
+			}
+		})
+	}
+}
+
+func TestTileIDRoundTrip(t *testing.T) {
+	for z := 0; z <= 5; z++ {
+		seen := make(map[uint64]bool)
+		n := 1 << uint(z)
+		for x := 0; x < n; x++ {
+			for y := 0; y < n; y++ {
+				tl := tiling.Tile{X: x, Y: y, Z: z}
+				id, err := pmtiles.TileID(tl)
+				if err != nil || seen[id] {
+					t.Fatalf("Tile %+v has a duplicated or invalid id %d %v", tl, id, err)
+				}
+				seen[id] = true
+				if back, _ := pmtiles.TileOfID(id); back != tl {
+					t.Errorf("Tile is different (expected, actual) %+v != %+v", tl, back)
+				}
+			}
+		}
+	}
+	if _, err := pmtiles.TileID(tiling.Tile{X: 2, Y: 0, Z: 1}); err == nil {
+		t.Errorf("Tile out of the zoom level should fail")
+	}
+}
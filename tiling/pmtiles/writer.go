@@ -0,0 +1,184 @@
+package pmtiles
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//WriterOptions describe the archive produced by a Writer
+type WriterOptions struct {
+	//TileType is the format of the tiles
+	TileType TileType
+	//TileCompression is the compression already applied to the tile data given to the Writer
+	TileCompression Compression
+	//InternalCompression is the compression of directories and metadata, Gzip when unknown
+	InternalCompression Compression
+	//Bounds is the extent of the archive, the extent of the written tiles when empty
+	Bounds tiling.ExtentG
+	//Center and CenterZoom are the default view, the center of Bounds at the minimum zoom when Center is empty
+	Center     tiling.PointG
+	CenterZoom int
+	//Metadata is encoded as the JSON metadata of the archive
+	Metadata map[string]interface{}
+}
+
+//Writer builds a PMTiles archive: tiles are kept in memory, deduplicated by content,
+//and written clustered in tile id order by Finalize
+type Writer struct {
+	opts     WriterOptions
+	tiles    map[uint64]int
+	contents [][]byte
+	byHash   map[[sha256.Size]byte]int
+}
+
+//NewWriter creates a Writer with the given options
+func NewWriter(opts WriterOptions) *Writer {
+	if opts.InternalCompression == UnknownCompression {
+		opts.InternalCompression = Gzip
+	}
+	return &Writer{
+		opts:   opts,
+		tiles:  make(map[uint64]int),
+		byHash: make(map[[sha256.Size]byte]int),
+	}
+}
+
+//WriteTile adds the tile data to the archive, replacing the previous data of the same tile
+func (w *Writer) WriteTile(t tiling.Tile, data []byte) error {
+	id, err := TileID(t)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	idx, ok := w.byHash[sum]
+	if !ok {
+		idx = len(w.contents)
+		w.contents = append(w.contents, append([]byte(nil), data...))
+		w.byHash[sum] = idx
+	}
+	w.tiles[id] = idx
+	return nil
+}
+
+//Finalize writes the archive to out, returning its header as written
+func (w *Writer) Finalize(out io.Writer) (Header, error) {
+	if len(w.tiles) == 0 {
+		return Header{}, fmt.Errorf("Cannot write an archive without tiles")
+	}
+	ids := make([]uint64, 0, len(w.tiles))
+	for id := range w.tiles {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	h := Header{
+		Clustered:           true,
+		InternalCompression: w.opts.InternalCompression,
+		TileCompression:     w.opts.TileCompression,
+		TileType:            w.opts.TileType,
+		AddressedTiles:      uint64(len(ids)),
+		MinZoom:             -1,
+	}
+	var entries []entry
+	var data []byte
+	offsets := make(map[int]uint64)
+	var bounds tiling.ExtentG
+	lastIdx := -1
+	for i, id := range ids {
+		t, _ := TileOfID(id)
+		if h.MinZoom < 0 || t.Z < h.MinZoom {
+			h.MinZoom = t.Z
+		}
+		if t.Z > h.MaxZoom {
+			h.MaxZoom = t.Z
+		}
+		te := tiling.MercToGeoExt(tiling.ExtentOf(t))
+		if i == 0 {
+			bounds = te
+		} else {
+			bounds = unionG(bounds, te)
+		}
+		idx := w.tiles[id]
+		last := len(entries) - 1
+		if last >= 0 && idx == lastIdx && entries[last].TileID+uint64(entries[last].RunLength) == id {
+			entries[last].RunLength++
+			continue
+		}
+		lastIdx = idx
+		off, ok := offsets[idx]
+		if !ok {
+			off = uint64(len(data))
+			offsets[idx] = off
+			data = append(data, w.contents[idx]...)
+		}
+		entries = append(entries, entry{TileID: id, Offset: off, Length: uint32(len(w.contents[idx])), RunLength: 1})
+	}
+	h.TileEntries = uint64(len(entries))
+	h.TileContents = uint64(len(offsets))
+
+	root, leaves, err := buildDirectories(entries, h.InternalCompression)
+	if err != nil {
+		return h, err
+	}
+	md := w.opts.Metadata
+	if md == nil {
+		md = map[string]interface{}{}
+	}
+	rawMetadata, err := json.Marshal(md)
+	if err != nil {
+		return h, fmt.Errorf("Cannot encode metadata: %v", err)
+	}
+	metadata, err := compress(rawMetadata, h.InternalCompression)
+	if err != nil {
+		return h, err
+	}
+
+	h.Bounds = w.opts.Bounds
+	if h.Bounds == (tiling.ExtentG{}) {
+		h.Bounds = bounds
+	}
+	h.Center, h.CenterZoom = w.opts.Center, w.opts.CenterZoom
+	if h.Center == (tiling.PointG{}) {
+		h.Center = tiling.PointG{Lat: (h.Bounds.MinLat + h.Bounds.MaxLat) / 2, Lon: (h.Bounds.MinLon + h.Bounds.MaxLon) / 2}
+		h.CenterZoom = h.MinZoom
+	}
+	h.RootOffset = HeaderSize
+	h.RootLength = uint64(len(root))
+	h.MetadataOffset = h.RootOffset + h.RootLength
+	h.MetadataLength = uint64(len(metadata))
+	h.LeafOffset = h.MetadataOffset + h.MetadataLength
+	h.LeafLength = uint64(len(leaves))
+	h.TileDataOffset = h.LeafOffset + h.LeafLength
+	h.TileDataLength = uint64(len(data))
+
+	header := h.marshal()
+	for _, part := range [][]byte{header, root, metadata, leaves, data} {
+		if _, err := out.Write(part); err != nil {
+			return h, fmt.Errorf("Cannot write archive: %v", err)
+		}
+	}
+	//the written header has coordinates rounded to 1e-7 degrees
+	return unmarshalHeader(header)
+}
+
+//unionG gives the smallest geo extent covering both the extents
+func unionG(a, b tiling.ExtentG) tiling.ExtentG {
+	if b.MinLat < a.MinLat {
+		a.MinLat = b.MinLat
+	}
+	if b.MinLon < a.MinLon {
+		a.MinLon = b.MinLon
+	}
+	if b.MaxLat > a.MaxLat {
+		a.MaxLat = b.MaxLat
+	}
+	if b.MaxLon > a.MaxLon {
+		a.MaxLon = b.MaxLon
+	}
+	return a
+}
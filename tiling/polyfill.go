@@ -0,0 +1,268 @@
+package tiling
+
+import (
+	"math"
+	"sort"
+)
+
+//PolygonG is a polygon in geographic coordinates: the first ring is the outer boundary, the others are holes.
+//Rings may be open or closed, the last vertex is always connected to the first one.
+type PolygonG [][]PointG
+
+//PolygonM is a polygon in mercator coordinates: the first ring is the outer boundary, the others are holes.
+//Rings may be open or closed, the last vertex is always connected to the first one.
+type PolygonM [][]PointM
+
+//Coverage select which tiles are returned when covering a geometry
+type Coverage int
+
+const (
+	//Intersecting selects every tile touched by the geometry
+	Intersecting Coverage = iota
+	//Contained selects only the tiles fully inside the geometry
+	Contained
+)
+
+//GeoToMercPolygon convert the given geo polygon to mercator, latitudes are clamped to the tiling limits
+func GeoToMercPolygon(p PolygonG) PolygonM {
+	pm := make(PolygonM, len(p))
+	for i, ring := range p {
+		rm := make([]PointM, len(ring))
+		for j, g := range ring {
+			g.Lat = math.Max(tileMinLat, math.Min(tileMaxLat, g.Lat))
+			rm[j] = GeoToMerc(g)
+		}
+		pm[i] = rm
+	}
+	return pm
+}
+
+//PolygonTiles gives the tiles of the zoom level covering the given polygons, sorted by row and column.
+//With Intersecting every tile whose interior is crossed by a ring or lies inside a polygon is returned,
+//with Contained only the tiles inside a polygon that are not crossed by any ring.
+//Rings running along the tile edges only touch the tiles on both sides, which are not crossed.
+//Polygons must not cross the antimeridian.
+func (z *ZoomLevel) PolygonTiles(polys []PolygonM, mode Coverage) []Tile {
+	res := []Tile{}
+	for _, r := range z.PolygonRanges(polys, mode) {
+		r.Each(func(t Tile) bool {
+			res = append(res, t)
+			return true
+		})
+	}
+	return res
+}
+
+//PolygonRanges gives the tiles of PolygonTiles as ranges of adjacent tiles of a single row, sorted by row and column.
+//Only the tiles crossed by the rings are held while building them, so the polygon interior can span
+//any number of tiles.
+func (z *ZoomLevel) PolygonRanges(polys []PolygonM, mode Coverage) []Range {
+	rows := make(map[int][][2]int)
+	for _, p := range polys {
+		border := make(map[int][]int)
+		for _, ring := range p {
+			for i := range ring {
+				z.crossedTiles(ring[i], ring[(i+1)%len(ring)], func(t Tile) {
+					border[t.Y] = append(border[t.Y], t.X)
+				})
+			}
+		}
+		for y, xs := range border {
+			sort.Ints(xs)
+			if mode == Intersecting {
+				for _, x := range xs {
+					rows[y] = append(rows[y], [2]int{x, x})
+				}
+			}
+		}
+		z.fill(p, func(y, minX, maxX int) {
+			if mode == Intersecting {
+				rows[y] = append(rows[y], [2]int{minX, maxX})
+				return
+			}
+			xs := border[y]
+			for i := sort.SearchInts(xs, minX); i < len(xs) && xs[i] <= maxX; i++ {
+				if xs[i] > minX {
+					rows[y] = append(rows[y], [2]int{minX, xs[i] - 1})
+				}
+				minX = xs[i] + 1
+			}
+			if minX <= maxX {
+				rows[y] = append(rows[y], [2]int{minX, maxX})
+			}
+		})
+	}
+	var res []Range
+	for y, spans := range rows {
+		sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+		r := Range{MinX: spans[0][0], MaxX: spans[0][1], MinY: y, MaxY: y, ZL: z.zoom}
+		for _, s := range spans[1:] {
+			if s[0] <= r.MaxX+1 {
+				r.MaxX = maxInt(r.MaxX, s[1])
+				continue
+			}
+			res = append(res, ConvertRange(r, XYZ, z.scheme))
+			r.MinX, r.MaxX = s[0], s[1]
+		}
+		res = append(res, ConvertRange(r, XYZ, z.scheme))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].MinY != res[j].MinY {
+			return res[i].MinY < res[j].MinY
+		}
+		return res[i].MinX < res[j].MinX
+	})
+	return res
+}
+
+//fill calls fn for every span of XYZ tiles of a row, from minX to maxX included, whose centers lie inside
+//the polygon, following the even-odd rule
+func (z *ZoomLevel) fill(p PolygonM, fn func(y, minX, maxX int)) {
+	minN, maxN := math.Inf(1), math.Inf(-1)
+	for _, ring := range p {
+		for _, v := range ring {
+			minN = math.Min(minN, v.N)
+			maxN = math.Max(maxN, v.N)
+		}
+	}
+	if minN > maxN {
+		return
+	}
+	last := int(z.mxSize) - 1
+	minY := clampInt(int(math.Floor((meridian-maxN)/z.vLength)), 0, last)
+	maxY := clampInt(int(math.Floor((meridian-minN)/z.vLength)), 0, last)
+	var xs []float64
+	for y := minY; y <= maxY; y++ {
+		n := meridian - (float64(y)+0.5)*z.vLength
+		xs = xs[:0]
+		for _, ring := range p {
+			for i := range ring {
+				a, b := ring[i], ring[(i+1)%len(ring)]
+				if (a.N > n) != (b.N > n) {
+					xs = append(xs, a.E+(n-a.N)*(b.E-a.E)/(b.N-a.N))
+				}
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			minX := clampInt(int(math.Ceil((xs[i]+equator/2)/z.hLength-0.5)), 0, last+1)
+			maxX := clampInt(int(math.Floor((xs[i+1]+equator/2)/z.hLength-0.5)), -1, last)
+			if minX <= maxX {
+				fn(y, minX, maxX)
+			}
+		}
+	}
+}
+
+//gridSnap is the distance, in tiles, within which a ring vertex is moved on the nearest tile edge,
+//absorbing the rounding of coordinates computed from the tile extents
+const gridSnap = 1e-6
+
+//snapToGrid gives the fractional tile coordinate f, moved on the nearest tile edge when within gridSnap
+func snapToGrid(f float64) float64 {
+	if r := math.Round(f); math.Abs(f-r) < gridSnap {
+		return r
+	}
+	return f
+}
+
+//crossedTiles calls fn, in order, for every XYZ tile whose interior is crossed by the segment from a to b.
+//A segment lying on a tile edge, or ending on it, only touches the tiles on the two sides of the edge and
+//crosses none of them. Tiles outside the zoom level bounds are skipped.
+func (z *ZoomLevel) crossedTiles(a, b PointM, fn func(Tile)) {
+	fx0, fy0 := snapToGrid((a.E+equator/2)/z.hLength), snapToGrid((meridian-a.N)/z.vLength)
+	fx1, fy1 := snapToGrid((b.E+equator/2)/z.hLength), snapToGrid((meridian-b.N)/z.vLength)
+	dx, dy := fx1-fx0, fy1-fy0
+	//the parameters where the segment meets the tile edges split it in pieces lying in a single tile
+	ts := []float64{0, 1}
+	edges := func(f0, d float64) {
+		if d == 0 {
+			return
+		}
+		lo, hi := math.Min(f0, f0+d), math.Max(f0, f0+d)
+		for k := math.Floor(lo) + 1; k < hi; k++ {
+			ts = append(ts, (k-f0)/d)
+		}
+	}
+	edges(fx0, dx)
+	edges(fy0, dy)
+	sort.Float64s(ts)
+	last := int(z.mxSize) - 1
+	for i := 0; i+1 < len(ts); i++ {
+		if ts[i+1] <= ts[i] {
+			continue
+		}
+		m := (ts[i] + ts[i+1]) / 2
+		mx, my := fx0+m*dx, fy0+m*dy
+		if (dx == 0 && mx == math.Floor(mx)) || (dy == 0 && my == math.Floor(my)) {
+			continue
+		}
+		x, y := int(math.Floor(mx)), int(math.Floor(my))
+		if x >= 0 && x <= last && y >= 0 && y <= last {
+			fn(Tile{X: x, Y: y, Z: z.zoom})
+		}
+	}
+}
+
+//traverse calls fn, in order, for every XYZ tile crossed by the segment from a to b until fn returns false.
+//Tiles outside the zoom level bounds are skipped.
+func (z *ZoomLevel) traverse(a, b PointM, fn func(Tile) bool) bool {
+	fx0, fy0 := (a.E+equator/2)/z.hLength, (meridian-a.N)/z.vLength
+	fx1, fy1 := (b.E+equator/2)/z.hLength, (meridian-b.N)/z.vLength
+	x, y := int(math.Floor(fx0)), int(math.Floor(fy0))
+	endX, endY := int(math.Floor(fx1)), int(math.Floor(fy1))
+	dx, dy := fx1-fx0, fy1-fy0
+	stepX, stepY := 1, 1
+	tMaxX, tMaxY := math.Inf(1), math.Inf(1)
+	tDeltaX, tDeltaY := math.Inf(1), math.Inf(1)
+	if dx < 0 {
+		stepX = -1
+	}
+	if dy < 0 {
+		stepY = -1
+	}
+	if dx != 0 {
+		tDeltaX = math.Abs(1 / dx)
+		if dx > 0 {
+			tMaxX = (float64(x+1) - fx0) / dx
+		} else {
+			tMaxX = (float64(x) - fx0) / dx
+		}
+	}
+	if dy != 0 {
+		tDeltaY = math.Abs(1 / dy)
+		if dy > 0 {
+			tMaxY = (float64(y+1) - fy0) / dy
+		} else {
+			tMaxY = (float64(y) - fy0) / dy
+		}
+	}
+	last := int(z.mxSize) - 1
+	steps := absInt(endX-x) + absInt(endY-y)
+	for i := 0; i <= steps; i++ {
+		if x >= 0 && x <= last && y >= 0 && y <= last {
+			if !fn(Tile{X: x, Y: y, Z: z.zoom}) {
+				return false
+			}
+		}
+		if tMaxX < tMaxY {
+			tMaxX += tDeltaX
+			x += stepX
+		} else {
+			tMaxY += tDeltaY
+			y += stepY
+		}
+	}
+	return true
+}
+
+func clampInt(v, lo, hi int) int {
+	return maxInt(lo, minInt(hi, v))
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
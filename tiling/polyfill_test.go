@@ -0,0 +1,156 @@
+package tiling_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//tilePoint gives the mercator point at the fractional tile coordinates (fx, fy) of zoom z
+func tilePoint(fx, fy float64, z int) tiling.PointM {
+	ext := tiling.ExtentOf(tiling.Tile{X: 0, Y: 0, Z: z})
+	size := ext.East - ext.West
+	return tiling.PointM{E: -20037508.342789244 + fx*size, N: 20037508.342789244 - fy*size}
+}
+
+func rectRing(x0, y0, x1, y1 float64, z int) []tiling.PointM {
+	return []tiling.PointM{
+		tilePoint(x0, y0, z), tilePoint(x1, y0, z), tilePoint(x1, y1, z), tilePoint(x0, y1, z), tilePoint(x0, y0, z),
+	}
+}
+
+func rangeTiles(minX, maxX, minY, maxY, z int, skip map[tiling.Tile]bool) []tiling.Tile {
+	var res []tiling.Tile
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			t := tiling.Tile{X: x, Y: y, Z: z}
+			if !skip[t] {
+				res = append(res, t)
+			}
+		}
+	}
+	return res
+}
+
+func TestPolygonTiles(t *testing.T) {
+	hole := map[tiling.Tile]bool{tiling.Tile{X: 4, Y: 5, Z: 4}: true}
+	polys := [][]tiling.PolygonM{
+		{tiling.PolygonM{rectRing(2.5, 3.5, 6.5, 7.5, 4)}},
+		{tiling.PolygonM{rectRing(2.5, 3.5, 6.5, 7.5, 4)}},
+		{tiling.PolygonM{rectRing(2.5, 3.5, 6.5, 7.5, 4), rectRing(4.2, 5.2, 4.8, 5.8, 4)}},
+		{tiling.PolygonM{rectRing(1.1, 1.1, 2.9, 2.9, 2)}},
+		{tiling.PolygonM{rectRing(1.1, 1.1, 2.9, 2.9, 2)}},
+		{tiling.PolygonM{rectRing(0.2, 0.2, 0.8, 0.8, 3)}, tiling.PolygonM{rectRing(5.2, 6.2, 5.8, 6.8, 3)}},
+	}
+	modes := []tiling.Coverage{tiling.Intersecting, tiling.Contained, tiling.Contained, tiling.Intersecting, tiling.Contained, tiling.Intersecting}
+	zooms := []int{4, 4, 4, 2, 2, 3}
+	expected := [][]tiling.Tile{
+		rangeTiles(2, 6, 3, 7, 4, nil),
+		rangeTiles(3, 5, 4, 6, 4, nil),
+		rangeTiles(3, 5, 4, 6, 4, hole),
+		rangeTiles(1, 2, 1, 2, 2, nil),
+		nil,
+		{tiling.Tile{X: 0, Y: 0, Z: 3}, tiling.Tile{X: 5, Y: 6, Z: 3}},
+	}
+	for i, p := range polys {
+		t.Run(fmt.Sprintf("Polygons %d mode %d", i, modes[i]), func(t *testing.T) {
+			ts := tiling.NewZoomLevel(zooms[i]).PolygonTiles(p, modes[i])
+			if len(ts) != len(expected[i]) {
+				t.Fatalf("Got %d tiles instead of %d: %+v", len(ts), len(expected[i]), ts)
+			}
+			for j := range ts {
+				if ts[j] != expected[i][j] {
+					t.Errorf("Tile %d is different (expected, actual) %+v != %+v", j, expected[i][j], ts[j])
+				}
+			}
+		})
+	}
+}
+
+func TestPolygonTilesAligned(t *testing.T) {
+	z := tiling.NewZoomLevel(4)
+	ul, lr := z.ExtentOfTile(2, 3), z.ExtentOfTile(3, 4)
+	e := tiling.NewExtentM(ul.UL(), lr.LR())
+	ccw := []tiling.PointM{e.LL(), e.LR(), e.UR(), e.UL()}
+	cw := []tiling.PointM{e.UL(), e.UR(), e.LR(), e.LL(), e.UL()}
+	expected := rangeTiles(2, 3, 3, 4, 4, nil)
+	for _, mode := range []tiling.Coverage{tiling.Intersecting, tiling.Contained} {
+		for i, ring := range [][]tiling.PointM{ccw, cw, rectRing(2, 3, 4, 5, 4)} {
+			t.Run(fmt.Sprintf("Ring %d mode %d", i, mode), func(t *testing.T) {
+				ts := z.PolygonTiles([]tiling.PolygonM{{ring}}, mode)
+				if len(ts) != len(expected) {
+					t.Fatalf("Got %d tiles instead of %d: %+v", len(ts), len(expected), ts)
+				}
+				for j := range ts {
+					if ts[j] != expected[j] {
+						t.Errorf("Tile %d is different (expected, actual) %+v != %+v", j, expected[j], ts[j])
+					}
+				}
+			})
+		}
+	}
+	//a ring with an edge along a grid line and a diagonal crossing tiles
+	ring := []tiling.PointM{tilePoint(2, 3, 4), tilePoint(4, 3, 4), tilePoint(2, 5, 4)}
+	ts := z.PolygonTiles([]tiling.PolygonM{{ring}}, tiling.Intersecting)
+	tri := []tiling.Tile{{X: 2, Y: 3, Z: 4}, {X: 3, Y: 3, Z: 4}, {X: 2, Y: 4, Z: 4}}
+	if len(ts) != len(tri) || ts[0] != tri[0] || ts[1] != tri[1] || ts[2] != tri[2] {
+		t.Errorf("Triangle tiles are different (expected, actual) %+v != %+v", tri, ts)
+	}
+	if ts := z.PolygonTiles([]tiling.PolygonM{{ring}}, tiling.Contained); len(ts) != 1 || ts[0] != tri[0] {
+		t.Errorf("Contained triangle tiles are different (expected, actual) %+v != %+v", tri[:1], ts)
+	}
+}
+
+func TestPolygonTilesTriangle(t *testing.T) {
+	const z = 6
+	tri := tiling.PolygonM{[]tiling.PointM{tilePoint(10.3, 10.7, z), tilePoint(30.6, 12.2, z), tilePoint(14.1, 40.9, z)}}
+	zl := tiling.NewZoomLevel(z)
+	intersecting := zl.PolygonTiles([]tiling.PolygonM{tri}, tiling.Intersecting)
+	contained := zl.PolygonTiles([]tiling.PolygonM{tri}, tiling.Contained)
+	in := make(map[tiling.Tile]bool)
+	for _, tl := range intersecting {
+		in[tl] = true
+	}
+	for _, tl := range contained {
+		if !in[tl] {
+			t.Errorf("Contained tile %+v is not among the intersecting ones", tl)
+		}
+		ext := zl.ExtentOfTile(tl.X, tl.Y)
+		for _, c := range []tiling.PointM{ext.UL(), ext.UR(), ext.LR(), ext.LL()} {
+			if !insideTriangle(c, tri[0]) {
+				t.Errorf("Corner %+v of contained tile %+v is outside the triangle", c, tl)
+			}
+		}
+	}
+	if len(contained) == 0 || len(contained) >= len(intersecting) {
+		t.Errorf("Unexpected counts: %d contained, %d intersecting", len(contained), len(intersecting))
+	}
+	for _, v := range tri[0] {
+		if tl := zl.TileOfMerc(v); !in[tl] {
+			t.Errorf("Tile %+v of vertex %+v is missing", tl, v)
+		}
+	}
+}
+
+func insideTriangle(p tiling.PointM, tri []tiling.PointM) bool {
+	sign := func(a, b tiling.PointM) float64 {
+		return (b.E-a.E)*(p.N-a.N) - (b.N-a.N)*(p.E-a.E)
+	}
+	d1, d2, d3 := sign(tri[0], tri[1]), sign(tri[1], tri[2]), sign(tri[2], tri[0])
+	return (d1 >= 0 && d2 >= 0 && d3 >= 0) || (d1 <= 0 && d2 <= 0 && d3 <= 0)
+}
+
+func TestPolygonTilesScheme(t *testing.T) {
+	p := []tiling.PolygonM{tiling.PolygonM{rectRing(2.5, 3.5, 3.5, 3.9, 4)}}
+	xyz := tiling.NewZoomLevel(4).PolygonTiles(p, tiling.Intersecting)
+	tms := tiling.NewZoomLevel(4).WithScheme(tiling.TMS).PolygonTiles(p, tiling.Intersecting)
+	if len(xyz) != 2 || len(tms) != 2 {
+		t.Fatalf("Unexpected tiles %+v %+v", xyz, tms)
+	}
+	for i := range xyz {
+		if tiling.ConvertTile(xyz[i], tiling.XYZ, tiling.TMS) != tms[i] {
+			t.Errorf("TMS tile %+v does not match XYZ tile %+v", tms[i], xyz[i])
+		}
+	}
+}
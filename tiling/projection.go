@@ -12,7 +12,8 @@ const (
 	deg2rad            = math.Pi / 180
 )
 
-//PointM is a point in projected WebMercator coordinates (EPSG:3857) https://en.wikipedia.org/wiki/Web_Mercator_projection#Identifiers
+//PointM is a point in projected WebMercator coordinates (EPSG:3857) https://en.wikipedia.org/wiki/Web_Mercator_projection#Identifiers,
+//it also holds the northing and easting of the other projections implementing Projection
 type PointM struct {
 	N float64
 	E float64
@@ -92,6 +93,34 @@ func (e ExtentG) LL() PointG {
 	return PointG{Lat: e.MinLat, Lon: e.MinLon}
 }
 
+//Projection converts geographic WGS84 coordinates to the planar coordinates of a map projection and back
+type Projection interface {
+	//Forward projects the given geo point
+	Forward(g PointG) PointM
+	//Inverse gives the geo point of the given projected point
+	Inverse(m PointM) PointG
+	//EPSG returns the EPSG code of the projected reference system
+	EPSG() string
+}
+
+//WebMercator is the spherical Mercator projection of EPSG:3857, implemented by GeoToMerc and MercToGeo
+type WebMercator struct{}
+
+//Forward projects the given geo point with GeoToMerc
+func (WebMercator) Forward(g PointG) PointM {
+	return GeoToMerc(g)
+}
+
+//Inverse gives the geo point of the given mercator point with MercToGeo
+func (WebMercator) Inverse(m PointM) PointG {
+	return MercToGeo(m)
+}
+
+//EPSG returns the code of WebMercator
+func (WebMercator) EPSG() string {
+	return EPSG
+}
+
 //GeoToMerc convert the given geo point to mercator
 func GeoToMerc(g PointG) PointM {
 	radLat := g.Lat * deg2rad
@@ -118,7 +147,8 @@ func MercToGeoExt(me ExtentM) ExtentG {
 	return geoEx
 }
 
-//Intersection compute the intersection between two extent and false if it is empty
+//Intersection compute the intersection between two extent and false if it is empty,
+//use Intersections for extents crossing the antimeridian
 func Intersection(ext1, ext2 ExtentM) (ExtentM, bool) {
 	ix := ExtentM{}
 	ix.West = math.Max(ext1.West, ext2.West)
@@ -145,3 +175,47 @@ func Equals(ext1, ext2 ExtentM) bool {
 		return true
 	}
 }
+
+//ProjectExtent gives the extent in the projection covering the given geo extent,
+//edges are densified because the projection may bend them
+func ProjectExtent(p Projection, ge ExtentG) ExtentM {
+	ext := ExtentM{North: math.Inf(-1), South: math.Inf(1), East: math.Inf(-1), West: math.Inf(1)}
+	eachEdgePoint(ge.MinLon, ge.MinLat, ge.MaxLon, ge.MaxLat, func(x, y float64) {
+		m := p.Forward(PointG{Lat: y, Lon: x})
+		ext.North = math.Max(ext.North, m.N)
+		ext.South = math.Min(ext.South, m.N)
+		ext.East = math.Max(ext.East, m.E)
+		ext.West = math.Min(ext.West, m.E)
+	})
+	return ext
+}
+
+//UnprojectExtent gives the geo extent covering the given extent of the projection,
+//edges are densified because the projection may bend them
+func UnprojectExtent(p Projection, me ExtentM) ExtentG {
+	ext := ExtentG{MaxLat: math.Inf(-1), MinLat: math.Inf(1), MaxLon: math.Inf(-1), MinLon: math.Inf(1)}
+	eachEdgePoint(me.West, me.South, me.East, me.North, func(x, y float64) {
+		g := p.Inverse(PointM{N: y, E: x})
+		ext.MaxLat = math.Max(ext.MaxLat, g.Lat)
+		ext.MinLat = math.Min(ext.MinLat, g.Lat)
+		ext.MaxLon = math.Max(ext.MaxLon, g.Lon)
+		ext.MinLon = math.Min(ext.MinLon, g.Lon)
+	})
+	return ext
+}
+
+//edgeSamples is the number of points sampled along each edge of an extent when reprojecting it
+const edgeSamples = 16
+
+//eachEdgePoint calls fn for points sampled along the edges of the box
+func eachEdgePoint(minX, minY, maxX, maxY float64, fn func(x, y float64)) {
+	for i := 0; i <= edgeSamples; i++ {
+		f := float64(i) / edgeSamples
+		x := minX + f*(maxX-minX)
+		y := minY + f*(maxY-minY)
+		fn(x, minY)
+		fn(x, maxY)
+		fn(minX, y)
+		fn(maxX, y)
+	}
+}
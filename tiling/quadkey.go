@@ -0,0 +1,71 @@
+package tiling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//maxQuadkeyZoom is the deepest zoom whose X and Y still fit in a signed int
+const maxQuadkeyZoom = strconv.IntSize - 1
+
+//Quadkey returns the Bing Maps quadkey of the tile, one base-4 digit per zoom level.
+//The zoom 0 tile has the empty quadkey.
+//https://docs.microsoft.com/en-us/bingmaps/articles/bing-maps-tile-system
+func (t Tile) Quadkey() string {
+	var sb strings.Builder
+	sb.Grow(t.Z)
+	for i := t.Z; i > 0; i-- {
+		digit := byte('0')
+		mask := 1 << uint(i-1)
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		sb.WriteByte(digit)
+	}
+	return sb.String()
+}
+
+//TileOfQuadkey gives the tile identified by the given quadkey
+func TileOfQuadkey(qk string) (Tile, error) {
+	if len(qk) > maxQuadkeyZoom {
+		return Tile{}, fmt.Errorf("Quadkey too long: zoom %d exceeds the maximum of %d", len(qk), maxQuadkeyZoom)
+	}
+	t := Tile{Z: len(qk)}
+	for i := 0; i < len(qk); i++ {
+		t.X <<= 1
+		t.Y <<= 1
+		switch qk[i] {
+		case '0':
+		case '1':
+			t.X |= 1
+		case '2':
+			t.Y |= 1
+		case '3':
+			t.X |= 1
+			t.Y |= 1
+		default:
+			return Tile{}, fmt.Errorf("Invalid quadkey digit %q at position %d in %q", qk[i], i, qk)
+		}
+	}
+	return t, nil
+}
+
+//QuadkeyRange returns the half-open interval [from, to) of quadkeys that contains the given tile
+//and all its descendants when keys are sorted lexicographically.
+//An empty to means the interval is unbounded, that is the case of the zoom 0 tile
+//and of the tiles along the last branch of the pyramid.
+func QuadkeyRange(t Tile) (from, to string) {
+	from = t.Quadkey()
+	end := []byte(from)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < '3' {
+			end[i]++
+			return from, string(end[:i+1])
+		}
+	}
+	return from, ""
+}
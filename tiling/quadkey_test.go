@@ -0,0 +1,86 @@
+package tiling_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestQuadkey(t *testing.T) {
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 1, Y: 0, Z: 1},
+		tiling.Tile{X: 3, Y: 5, Z: 3},
+		tiling.Tile{X: 33, Y: 23, Z: 6},
+		tiling.Tile{X: 106960, Y: 75432, Z: 17},
+	}
+	keys := []string{
+		"",
+		"1",
+		"213",
+		"120223",
+		"31030022131212000",
+	}
+	for i, tl := range tiles {
+		t.Run(fmt.Sprintf("Tile %d (X,Y,Z)(%d, %d, %d)", i, tl.X, tl.Y, tl.Z), func(t *testing.T) {
+			qk := tl.Quadkey()
+			if qk != keys[i] {
+				t.Errorf("Quadkey is different (expected, actual) %q != %q", keys[i], qk)
+			}
+			back, err := tiling.TileOfQuadkey(qk)
+			if err != nil {
+				t.Fatalf("Unexpected error decoding %q: %v", qk, err)
+			}
+			if back != tl {
+				t.Errorf("Decoded tile is different (expected, actual) %+v != %+v", tl, back)
+			}
+		})
+	}
+}
+
+func TestTileOfQuadkeyErrors(t *testing.T) {
+	keys := []string{
+		"0124",
+		"a",
+		"12 3",
+		strings.Repeat("1", 64),
+	}
+	for _, qk := range keys {
+		t.Run(fmt.Sprintf("Quadkey %q", qk), func(t *testing.T) {
+			if tl, err := tiling.TileOfQuadkey(qk); err == nil {
+				t.Errorf("Expected an error, got tile %+v", tl)
+			}
+		})
+	}
+}
+
+func TestQuadkeyRange(t *testing.T) {
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 1, Y: 0, Z: 1},
+		tiling.Tile{X: 3, Y: 5, Z: 3},
+		tiling.Tile{X: 1, Y: 3, Z: 2},
+		tiling.Tile{X: 3, Y: 3, Z: 2},
+	}
+	bounds := [][]string{
+		{"", ""},
+		{"1", "2"},
+		{"213", "22"},
+		{"23", "3"},
+		{"33", ""},
+	}
+	for i, tl := range tiles {
+		t.Run(fmt.Sprintf("Tile %d (X,Y,Z)(%d, %d, %d)", i, tl.X, tl.Y, tl.Z), func(t *testing.T) {
+			from, to := tiling.QuadkeyRange(tl)
+			if from != bounds[i][0] || to != bounds[i][1] {
+				t.Errorf("Range is different (expected, actual) [%q, %q) != [%q, %q)", bounds[i][0], bounds[i][1], from, to)
+			}
+			child := tiling.Tile{X: tl.X * 2, Y: tl.Y*2 + 1, Z: tl.Z + 1}.Quadkey()
+			if child < from || (to != "" && child >= to) {
+				t.Errorf("Child key %q is outside [%q, %q)", child, from, to)
+			}
+		})
+	}
+}
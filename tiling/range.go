@@ -0,0 +1,150 @@
+package tiling
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+//Width gives the number of columns of the range
+func (r Range) Width() int {
+	return r.MaxX - r.MinX + 1
+}
+
+//Height gives the number of rows of the range
+func (r Range) Height() int {
+	return r.MaxY - r.MinY + 1
+}
+
+//Empty tells if the range does not contain any tile
+func (r Range) Empty() bool {
+	return r.MaxX < r.MinX || r.MaxY < r.MinY
+}
+
+//Cardinality gives the number of tiles in the range, computed in int64 to avoid overflows at deep zoom levels
+func (r Range) Cardinality() int64 {
+	if r.Empty() {
+		return 0
+	}
+	return int64(r.Width()) * int64(r.Height())
+}
+
+//Contains tells if the given tile is inside the range
+func (r Range) Contains(t Tile) bool {
+	return t.Z == r.ZL && t.X >= r.MinX && t.X <= r.MaxX && t.Y >= r.MinY && t.Y <= r.MaxY
+}
+
+//Each calls fn for every tile of the range, row by row, until fn returns false
+func (r Range) Each(fn func(Tile) bool) {
+	for y := r.MinY; y <= r.MaxY; y++ {
+		for x := r.MinX; x <= r.MaxX; x++ {
+			if !fn(Tile{X: x, Y: y, Z: r.ZL}) {
+				return
+			}
+		}
+	}
+}
+
+//Tiles streams the tiles of the range, row by row, on the returned channel.
+//The channel is closed when all the tiles are sent or when ctx is done.
+func (r Range) Tiles(ctx context.Context) <-chan Tile {
+	ch := make(chan Tile)
+	go func() {
+		defer close(ch)
+		r.Each(func(t Tile) bool {
+			select {
+			case ch <- t:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return ch
+}
+
+//RangeIntersection compute the intersection between two ranges and false if it is empty
+//or the ranges belong to different zoom levels
+func RangeIntersection(r1, r2 Range) (Range, bool) {
+	if r1.ZL != r2.ZL {
+		return Range{}, false
+	}
+	ix := Range{ZL: r1.ZL}
+	ix.MinX = maxInt(r1.MinX, r2.MinX)
+	ix.MaxX = minInt(r1.MaxX, r2.MaxX)
+	ix.MinY = maxInt(r1.MinY, r2.MinY)
+	ix.MaxY = minInt(r1.MaxY, r2.MaxY)
+	if ix.Empty() {
+		return Range{}, false
+	}
+	return ix, true
+}
+
+//RangeUnion compute the smallest range covering both the given ranges,
+//it fails if the ranges belong to different zoom levels
+func RangeUnion(r1, r2 Range) (Range, error) {
+	if r1.ZL != r2.ZL {
+		return Range{}, fmt.Errorf("Ranges belong to different zoom levels: %d and %d", r1.ZL, r2.ZL)
+	}
+	if r1.Empty() {
+		return r2, nil
+	}
+	if r2.Empty() {
+		return r1, nil
+	}
+	u := Range{ZL: r1.ZL}
+	u.MinX = minInt(r1.MinX, r2.MinX)
+	u.MaxX = maxInt(r1.MaxX, r2.MaxX)
+	u.MinY = minInt(r1.MinY, r2.MinY)
+	u.MaxY = maxInt(r1.MaxY, r2.MaxY)
+	return u, nil
+}
+
+//Split divides the range in at most n non overlapping ranges of roughly the same cardinality.
+//The range is cut in a grid of as many parts as possible up to n, preferring the grid whose parts are closest to square.
+func (r Range) Split(n int) []Range {
+	if r.Empty() || n <= 0 {
+		return nil
+	}
+	w, h := r.Width(), r.Height()
+	cols, rows := 1, 1
+	bestRatio := math.Inf(1)
+	for c := 1; c <= minInt(w, n); c++ {
+		rw := minInt(h, n/c)
+		pw, ph := float64(w)/float64(c), float64(h)/float64(rw)
+		ratio := math.Max(pw/ph, ph/pw)
+		if c*rw > cols*rows || (c*rw == cols*rows && ratio < bestRatio) {
+			cols, rows, bestRatio = c, rw, ratio
+		}
+	}
+	res := make([]Range, 0, cols*rows)
+	for j := 0; j < rows; j++ {
+		minY, maxY := splitBounds(r.MinY, h, rows, j)
+		for i := 0; i < cols; i++ {
+			minX, maxX := splitBounds(r.MinX, w, cols, i)
+			res = append(res, Range{MinX: minX, MaxX: maxX, MinY: minY, MaxY: maxY, ZL: r.ZL})
+		}
+	}
+	return res
+}
+
+//splitBounds gives the inclusive bounds of the i-th of n parts of length size starting at start
+func splitBounds(start, size, n, i int) (int, int) {
+	lo := start + int(int64(size)*int64(i)/int64(n))
+	hi := start + int(int64(size)*int64(i+1)/int64(n)) - 1
+	return lo, hi
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,153 @@
+package tiling_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestRangeCardinalityInt64(t *testing.T) {
+	ranges := []tiling.Range{
+		tiling.Range{MinX: 33, MaxX: 33, MinY: 23, MaxY: 23, ZL: 6},
+		tiling.Range{MinX: 24112, MaxX: 106961, MinY: 55639, MaxY: 75433, ZL: 17},
+		tiling.Range{MinX: 0, MaxX: 1<<30 - 1, MinY: 0, MaxY: 1<<30 - 1, ZL: 30},
+		tiling.Range{MinX: 5, MaxX: 4, MinY: 0, MaxY: 0, ZL: 3},
+	}
+	cards := []int64{1, 1640015750, 1 << 60, 0}
+	for i, r := range ranges {
+		t.Run(fmt.Sprintf("Range %d %+v", i, r), func(t *testing.T) {
+			if c := r.Cardinality(); c != cards[i] {
+				t.Errorf("Cardinality is different (expected, actual) %d != %d", cards[i], c)
+			}
+		})
+	}
+}
+
+func TestRangeEach(t *testing.T) {
+	r := tiling.Range{MinX: 3, MaxX: 5, MinY: 7, MaxY: 8, ZL: 4}
+	seen := make(map[tiling.Tile]bool)
+	r.Each(func(tl tiling.Tile) bool {
+		if !r.Contains(tl) {
+			t.Errorf("Tile %+v is not contained in the range", tl)
+		}
+		seen[tl] = true
+		return true
+	})
+	if int64(len(seen)) != r.Cardinality() {
+		t.Errorf("Visited %d tiles instead of %d", len(seen), r.Cardinality())
+	}
+	count := 0
+	r.Each(func(tl tiling.Tile) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		t.Errorf("Iteration did not stop, visited %d tiles", count)
+	}
+	if r.Contains(tiling.Tile{X: 3, Y: 7, Z: 5}) || r.Contains(tiling.Tile{X: 6, Y: 7, Z: 4}) {
+		t.Errorf("Range contains tiles outside of it")
+	}
+}
+
+func TestRangeTiles(t *testing.T) {
+	r := tiling.Range{MinX: 0, MaxX: 9, MinY: 0, MaxY: 9, ZL: 4}
+	count := 0
+	for range r.Tiles(context.Background()) {
+		count++
+	}
+	if count != 100 {
+		t.Errorf("Received %d tiles instead of 100", count)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := r.Tiles(ctx)
+	<-ch
+	cancel()
+	for range ch {
+	}
+}
+
+func TestRangeIntersection(t *testing.T) {
+	r1 := tiling.Range{MinX: 0, MaxX: 10, MinY: 0, MaxY: 10, ZL: 5}
+	tests := []tiling.Range{
+		tiling.Range{MinX: 5, MaxX: 15, MinY: -3, MaxY: 4, ZL: 5},
+		tiling.Range{MinX: 11, MaxX: 15, MinY: 0, MaxY: 4, ZL: 5},
+		tiling.Range{MinX: 0, MaxX: 10, MinY: 0, MaxY: 10, ZL: 6},
+	}
+	expected := []tiling.Range{
+		tiling.Range{MinX: 5, MaxX: 10, MinY: 0, MaxY: 4, ZL: 5},
+		tiling.Range{},
+		tiling.Range{},
+	}
+	oks := []bool{true, false, false}
+	for i, r2 := range tests {
+		t.Run(fmt.Sprintf("Range %d %+v", i, r2), func(t *testing.T) {
+			ix, ok := tiling.RangeIntersection(r1, r2)
+			if ok != oks[i] || ix != expected[i] {
+				t.Errorf("Intersection is different (expected, actual) %+v %v != %+v %v", expected[i], oks[i], ix, ok)
+			}
+		})
+	}
+}
+
+func TestRangeUnion(t *testing.T) {
+	r1 := tiling.Range{MinX: 0, MaxX: 10, MinY: 0, MaxY: 10, ZL: 5}
+	r2 := tiling.Range{MinX: 5, MaxX: 15, MinY: -3, MaxY: 4, ZL: 5}
+	u, err := tiling.RangeUnion(r1, r2)
+	expected := tiling.Range{MinX: 0, MaxX: 15, MinY: -3, MaxY: 10, ZL: 5}
+	if err != nil || u != expected {
+		t.Errorf("Union is different (expected, actual) %+v != %+v %v", expected, u, err)
+	}
+	if _, err := tiling.RangeUnion(r1, tiling.Range{ZL: 4}); err == nil {
+		t.Errorf("Union of different zoom levels should fail")
+	}
+}
+
+func TestRangeSplit(t *testing.T) {
+	ranges := []tiling.Range{
+		tiling.Range{MinX: 0, MaxX: 99, MinY: 0, MaxY: 9, ZL: 8},
+		tiling.Range{MinX: 0, MaxX: 9, MinY: 10, MaxY: 109, ZL: 8},
+		tiling.Range{MinX: 0, MaxX: 3, MinY: 0, MaxY: 3, ZL: 8},
+		tiling.Range{MinX: 0, MaxX: 2, MinY: 0, MaxY: 0, ZL: 8},
+		tiling.Range{MinX: 0, MaxX: 2, MinY: 0, MaxY: 1, ZL: 8},
+		tiling.Range{MinX: 0, MaxX: 4, MinY: 0, MaxY: 2, ZL: 8},
+		tiling.Range{MinX: 0, MaxX: 9, MinY: 0, MaxY: 0, ZL: 8},
+	}
+	ns := []int{7, 3, 10, 5, 4, 7, 4}
+	counts := []int{7, 3, 9, 3, 4, 6, 4}
+	for i, r := range ranges {
+		t.Run(fmt.Sprintf("Range %d %+v in %d", i, r, ns[i]), func(t *testing.T) {
+			parts := r.Split(ns[i])
+			if len(parts) != counts[i] {
+				t.Fatalf("Got %d parts instead of %d", len(parts), counts[i])
+			}
+			var total int64
+			var min, max int64 = r.Cardinality(), 0
+			seen := make(map[tiling.Tile]bool)
+			for _, p := range parts {
+				c := p.Cardinality()
+				total += c
+				if c < min {
+					min = c
+				}
+				if c > max {
+					max = c
+				}
+				p.Each(func(tl tiling.Tile) bool {
+					if seen[tl] || !r.Contains(tl) {
+						t.Errorf("Tile %+v is duplicated or outside the range", tl)
+					}
+					seen[tl] = true
+					return true
+				})
+			}
+			if total != r.Cardinality() {
+				t.Errorf("Parts cover %d tiles instead of %d", total, r.Cardinality())
+			}
+			if max-min > int64(r.Width()) && max-min > int64(r.Height()) {
+				t.Errorf("Parts are unbalanced: min %d max %d", min, max)
+			}
+		})
+	}
+}
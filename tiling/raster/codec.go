@@ -0,0 +1,69 @@
+package raster
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+//Format is the encoding of a raster tile
+type Format int
+
+const (
+	//PNG is the lossless format, keeping transparency
+	PNG Format = iota
+	//JPEG is the lossy format, transparent pixels become black
+	JPEG
+)
+
+//DefaultJPEGQuality is the quality used when encoding JPEG tiles with a zero quality
+const DefaultJPEGQuality = 85
+
+//String returns the name of the format as used by image.Decode
+func (f Format) String() string {
+	switch f {
+	case PNG:
+		return "png"
+	case JPEG:
+		return "jpeg"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+//EncodeTile encodes the image in the given format, quality applies only to JPEG
+func EncodeTile(img image.Image, f Format, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch f {
+	case PNG:
+		err = png.Encode(&buf, img)
+	case JPEG:
+		if quality <= 0 {
+			quality = DefaultJPEGQuality
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	default:
+		return nil, fmt.Errorf("Unsupported format %v", f)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//DecodeTile decodes a PNG or JPEG tile, giving its format
+func DecodeTile(data []byte) (image.Image, Format, error) {
+	img, name, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, err
+	}
+	switch name {
+	case "png":
+		return img, PNG, nil
+	case "jpeg":
+		return img, JPEG, nil
+	}
+	return nil, 0, fmt.Errorf("Unsupported format %s", name)
+}
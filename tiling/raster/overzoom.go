@@ -0,0 +1,64 @@
+package raster
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//region gives the position of the inner extent in pixels of a raster of the given size covering the outer extent
+func region(outer, inner tiling.ExtentM, width, height int) (x0, y0, x1, y1 float64) {
+	sx := float64(width) / (outer.East - outer.West)
+	sy := float64(height) / (outer.North - outer.South)
+	return (inner.West - outer.West) * sx, (outer.North - inner.North) * sy, (inner.East - outer.West) * sx, (outer.North - inner.South) * sy
+}
+
+//resample draws the part of src between the continuous coordinates (x0, y0) and (x1, y1) into the rectangle r of dst
+func resample(dst *image.RGBA, r image.Rectangle, src *grid, x0, y0, x1, y1 float64, rs Resampling) {
+	sx := (x1 - x0) / float64(r.Dx())
+	sy := (y1 - y0) / float64(r.Dy())
+	v := make([]float64, src.bands)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			src.sample(x0+(float64(x-r.Min.X)+0.5)*sx, y0+(float64(y-r.Min.Y)+0.5)*sy, rs, v)
+			setRGBA(dst, x, y, v)
+		}
+	}
+}
+
+//Overzoom synthesizes the image of the tile child, size pixels wide, upsampling the region it covers in img,
+//the image of its ancestor parent
+func Overzoom(parent tiling.Tile, img image.Image, child tiling.Tile, size int, rs Resampling) (*image.RGBA, error) {
+	if !parent.Contains(child) || parent == child {
+		return nil, fmt.Errorf("Tile %+v is not a descendant of %+v", child, parent)
+	}
+	b := img.Bounds()
+	x0, y0, x1, y1 := region(tiling.ExtentOf(parent), tiling.ExtentOf(child), b.Dx(), b.Dy())
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	resample(dst, dst.Bounds(), gridOfImage(img), x0, y0, x1, y1, rs)
+	return dst, nil
+}
+
+//Downsample builds the image of the tile parent, size pixels wide, from the images of its children.
+//Missing children leave their quadrant transparent.
+func Downsample(parent tiling.Tile, children map[tiling.Tile]image.Image, size int, rs Resampling) (*image.RGBA, error) {
+	for c := range children {
+		if p, err := c.Parent(); err != nil || p != parent {
+			return nil, fmt.Errorf("Tile %+v is not a child of %+v", c, parent)
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	pExt := tiling.ExtentOf(parent)
+	for _, c := range parent.Children() {
+		img, ok := children[c]
+		if !ok {
+			continue
+		}
+		x0, y0, x1, y1 := region(pExt, tiling.ExtentOf(c), size, size)
+		r := image.Rect(int(x0+0.5), int(y0+0.5), int(x1+0.5), int(y1+0.5))
+		b := img.Bounds()
+		resample(dst, r, gridOfImage(img), 0, 0, float64(b.Dx()), float64(b.Dy()), rs)
+	}
+	return dst, nil
+}
@@ -0,0 +1,130 @@
+//Package raster builds and transforms raster tiles addressed with tiling.Tile in the XYZ scheme
+package raster
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+//Resampling selects how pixel values are interpolated when a raster is scaled or warped
+type Resampling int
+
+const (
+	//Nearest takes the value of the nearest pixel
+	Nearest Resampling = iota
+	//Bilinear interpolates the 2x2 nearest pixels
+	Bilinear
+	//Cubic interpolates the 4x4 nearest pixels with a Catmull-Rom spline
+	Cubic
+)
+
+//grid is a raster of float values, bands are interleaved by pixel.
+//Pixel (i, j) covers the square from (i, j) to (i+1, j+1) of the continuous grid coordinates.
+type grid struct {
+	width  int
+	height int
+	bands  int
+	data   []float64
+}
+
+func newGrid(width, height, bands int) *grid {
+	return &grid{width: width, height: height, bands: bands, data: make([]float64, width*height*bands)}
+}
+
+//gridOfImage converts the image to a grid of premultiplied RGBA values in [0, 1]
+func gridOfImage(img image.Image) *grid {
+	b := img.Bounds()
+	g := newGrid(b.Dx(), b.Dy(), 4)
+	i := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, gr, bl, a := img.At(x, y).RGBA()
+			g.data[i], g.data[i+1], g.data[i+2], g.data[i+3] = float64(r)/0xffff, float64(gr)/0xffff, float64(bl)/0xffff, float64(a)/0xffff
+			i += 4
+		}
+	}
+	return g
+}
+
+//pixel gives the values of the pixel (x, y), clamped to the grid bounds
+func (g *grid) pixel(x, y int) []float64 {
+	if x < 0 {
+		x = 0
+	} else if x >= g.width {
+		x = g.width - 1
+	}
+	if y < 0 {
+		y = 0
+	} else if y >= g.height {
+		y = g.height - 1
+	}
+	i := (y*g.width + x) * g.bands
+	return g.data[i : i+g.bands]
+}
+
+//sample interpolates the values at the continuous grid coordinates (x, y) into dst
+func (g *grid) sample(x, y float64, r Resampling, dst []float64) {
+	for b := range dst {
+		dst[b] = 0
+	}
+	switch r {
+	case Bilinear:
+		x0, y0 := math.Floor(x-0.5), math.Floor(y-0.5)
+		tx, ty := x-0.5-x0, y-0.5-y0
+		ix, iy := int(x0), int(y0)
+		for j := 0; j < 2; j++ {
+			wy := 1 - ty
+			if j == 1 {
+				wy = ty
+			}
+			for i := 0; i < 2; i++ {
+				wx := 1 - tx
+				if i == 1 {
+					wx = tx
+				}
+				g.accumulate(ix+i, iy+j, wx*wy, dst)
+			}
+		}
+	case Cubic:
+		x0, y0 := math.Floor(x-0.5), math.Floor(y-0.5)
+		wxs, wys := cubicWeights(x-0.5-x0), cubicWeights(y-0.5-y0)
+		ix, iy := int(x0), int(y0)
+		for j := -1; j <= 2; j++ {
+			for i := -1; i <= 2; i++ {
+				g.accumulate(ix+i, iy+j, wxs[i+1]*wys[j+1], dst)
+			}
+		}
+	default:
+		copy(dst, g.pixel(int(math.Floor(x)), int(math.Floor(y))))
+	}
+}
+
+func (g *grid) accumulate(x, y int, w float64, dst []float64) {
+	if w == 0 {
+		return
+	}
+	for b, v := range g.pixel(x, y) {
+		dst[b] += w * v
+	}
+}
+
+//cubicWeights gives the Catmull-Rom weights of the 4 samples around the offset t in [0, 1)
+func cubicWeights(t float64) [4]float64 {
+	t2, t3 := t*t, t*t*t
+	return [4]float64{
+		(-t3 + 2*t2 - t) / 2,
+		(3*t3 - 5*t2 + 2) / 2,
+		(-3*t3 + 4*t2 + t) / 2,
+		(t3 - t2) / 2,
+	}
+}
+
+//setRGBA writes premultiplied values in [0, 1] to the pixel (x, y) of img, clamping the overshoots of cubic interpolation
+func setRGBA(img *image.RGBA, x, y int, v []float64) {
+	c := func(f, max float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(max, f)) * 255))
+	}
+	a := math.Max(0, math.Min(1, v[3]))
+	img.SetRGBA(x, y, color.RGBA{R: c(v[0], a), G: c(v[1], a), B: c(v[2], a), A: c(a, 1)})
+}
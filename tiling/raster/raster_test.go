@@ -0,0 +1,131 @@
+package raster_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/raster"
+)
+
+var (
+	red   = color.RGBA{R: 255, A: 255}
+	green = color.RGBA{G: 255, A: 255}
+	blue  = color.RGBA{B: 255, A: 255}
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+//quadrants gives an image whose quadrants have the colors of cs in quadkey order
+func quadrants(size int, cs ...color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetRGBA(x, y, cs[(2*y/size)*2+2*x/size])
+		}
+	}
+	return img
+}
+
+func solid(size int, c color.RGBA) *image.RGBA {
+	return quadrants(size, c, c, c, c)
+}
+
+func TestOverzoom(t *testing.T) {
+	parent := tiling.Tile{X: 5, Y: 3, Z: 4}
+	img := quadrants(256, red, green, blue, white)
+	children := parent.Children()
+	colors := []color.RGBA{red, green, blue, white}
+	for i, c := range children {
+		out, err := raster.Overzoom(parent, img, c, 256, raster.Nearest)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, p := range []image.Point{{0, 0}, {255, 255}, {128, 17}} {
+			if px := out.RGBAAt(p.X, p.Y); px != colors[i] {
+				t.Errorf("Pixel %v of child %+v is different (expected, actual) %v != %v", p, c, colors[i], px)
+			}
+		}
+	}
+	grandchildren := []tiling.Tile{{X: 5*4 + 1, Y: 3*4 + 1, Z: 6}, {X: 5*4 + 2, Y: 3*4 + 2, Z: 6}}
+	colors = []color.RGBA{red, white}
+	for i, gc := range grandchildren {
+		for _, rs := range []raster.Resampling{raster.Nearest, raster.Bilinear, raster.Cubic} {
+			out, err := raster.Overzoom(parent, img, gc, 256, rs)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, p := range []image.Point{{10, 10}, {245, 245}} {
+				if px := out.RGBAAt(p.X, p.Y); px != colors[i] {
+					t.Errorf("Pixel %v of %+v with resampling %d is different (expected, actual) %v != %v", p, gc, rs, colors[i], px)
+				}
+			}
+		}
+	}
+	if _, err := raster.Overzoom(parent, img, tiling.Tile{X: 0, Y: 0, Z: 5}, 256, raster.Nearest); err == nil {
+		t.Errorf("A tile outside the parent should fail")
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	parent := tiling.Tile{X: 5, Y: 3, Z: 4}
+	cs := parent.Children()
+	children := map[tiling.Tile]image.Image{cs[0]: solid(256, red), cs[1]: solid(256, green), cs[3]: solid(256, white)}
+	out, err := raster.Downsample(parent, children, 256, raster.Bilinear)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []color.RGBA{red, green, {}, white}
+	for i, p := range []image.Point{{0, 0}, {255, 0}, {0, 255}, {255, 255}} {
+		if px := out.RGBAAt(p.X, p.Y); px != expected[i] {
+			t.Errorf("Pixel %v is different (expected, actual) %v != %v", p, expected[i], px)
+		}
+	}
+	checker := image.NewRGBA(image.Rect(0, 0, 256, 256))
+	for y := 0; y < 256; y++ {
+		for x := 0; x < 256; x++ {
+			if (x+y)%2 == 0 {
+				checker.SetRGBA(x, y, white)
+			} else {
+				checker.SetRGBA(x, y, color.RGBA{A: 255})
+			}
+		}
+	}
+	out, err = raster.Downsample(parent, map[tiling.Tile]image.Image{cs[2]: checker}, 256, raster.Bilinear)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if px := out.RGBAAt(40, 200); px.R != 128 || px.A != 255 {
+		t.Errorf("Downsampled checkerboard should be gray, got %v", px)
+	}
+	if _, err := raster.Downsample(parent, map[tiling.Tile]image.Image{{X: 0, Y: 0, Z: 5}: checker}, 256, raster.Nearest); err == nil {
+		t.Errorf("A tile that is not a child should fail")
+	}
+}
+
+func TestCodec(t *testing.T) {
+	img := quadrants(64, red, green, blue, white)
+	for _, f := range []raster.Format{raster.PNG, raster.JPEG} {
+		data, err := raster.EncodeTile(img, f, 0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		dec, format, err := raster.DecodeTile(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if format != f || dec.Bounds() != img.Bounds() {
+			t.Errorf("Decoded %v %v instead of %v %v", format, dec.Bounds(), f, img.Bounds())
+		}
+		r, g, b, _ := dec.At(5, 5).RGBA()
+		if r < 0xf000 || g > 0x1000 || b > 0x1000 {
+			t.Errorf("Format %v does not keep the colors: %x %x %x", f, r, g, b)
+		}
+	}
+	if _, err := raster.EncodeTile(img, raster.Format(9), 0); err == nil {
+		t.Errorf("An unknown format should fail")
+	}
+	if _, _, err := raster.DecodeTile([]byte("not an image")); err == nil {
+		t.Errorf("Invalid data should fail")
+	}
+}
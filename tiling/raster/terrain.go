@@ -0,0 +1,161 @@
+package raster
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//Elevation is the encoding of elevations in the RGB channels of a tile
+type Elevation int
+
+const (
+	//TerrainRGB is the Mapbox encoding: height = -10000 + (R*256*256 + G*256 + B) * 0.1
+	//https://docs.mapbox.com/data/tilesets/reference/mapbox-terrain-rgb-v1/
+	TerrainRGB Elevation = iota
+	//Terrarium is the Mapzen encoding: height = R*256 + G + B/256 - 32768
+	//https://github.com/tilezen/joerd/blob/master/docs/formats.md#terrarium
+	Terrarium
+)
+
+//EncodeElevation converts the elevations in meters of the single band tile grid to an image with the given encoding,
+//missing pixels are transparent
+func EncodeElevation(tg *TileGrid, enc Elevation) (*image.RGBA, error) {
+	if tg.Bands != 1 {
+		return nil, fmt.Errorf("Elevation grid has %d bands instead of 1", tg.Bands)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, tg.Size, tg.Size))
+	for y := 0; y < tg.Size; y++ {
+		for x := 0; x < tg.Size; x++ {
+			if tg.Missing(x, y) {
+				continue
+			}
+			h := tg.At(x, y)[0]
+			var c color.RGBA
+			switch enc {
+			case TerrainRGB:
+				v := uint32(math.Max(0, math.Min(1<<24-1, math.Round((h+10000)*10))))
+				c = color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+			case Terrarium:
+				v := math.Max(0, math.Min(65536-1.0/256, h+32768))
+				c = color.RGBA{R: uint8(v / 256), G: uint8(math.Mod(v, 256)), B: uint8((v - math.Floor(v)) * 256), A: 255}
+			default:
+				return nil, fmt.Errorf("Unsupported elevation encoding %d", enc)
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img, nil
+}
+
+//DecodeElevation reads the elevations in meters of the square image of the tile t with the given encoding,
+//transparent pixels are missing with value NaN
+func DecodeElevation(t tiling.Tile, img image.Image, enc Elevation) (*TileGrid, error) {
+	b := img.Bounds()
+	if b.Dx() != b.Dy() {
+		return nil, fmt.Errorf("Elevation tile is %dx%d pixels", b.Dx(), b.Dy())
+	}
+	if enc != TerrainRGB && enc != Terrarium {
+		return nil, fmt.Errorf("Unsupported elevation encoding %d", enc)
+	}
+	tg := NewTileGrid(t, b.Dx(), 1, math.NaN())
+	for y := 0; y < tg.Size; y++ {
+		for x := 0; x < tg.Size; x++ {
+			c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+			r, g, bl := float64(c.R), float64(c.G), float64(c.B)
+			if enc == TerrainRGB {
+				tg.At(x, y)[0] = -10000 + (r*256*256+g*256+bl)*0.1
+			} else {
+				tg.At(x, y)[0] = r*256 + g + bl/256 - 32768
+			}
+		}
+	}
+	return tg, nil
+}
+
+//EncodeElevationTile encodes the elevations of the tile grid as a PNG tile with the given encoding
+func EncodeElevationTile(tg *TileGrid, enc Elevation) ([]byte, error) {
+	img, err := EncodeElevation(tg, enc)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeTile(img, PNG, 0)
+}
+
+//DecodeElevationTile decodes the elevations of the PNG tile t with the given encoding
+func DecodeElevationTile(t tiling.Tile, data []byte, enc Elevation) (*TileGrid, error) {
+	img, _, err := DecodeTile(data)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeElevation(t, img, enc)
+}
+
+//gradients calls fn with the eastward and southward elevation gradients of every valid pixel of the elevation grid,
+//computed with the Horn method over the ground resolution at the latitude of the pixel row.
+//Missing neighbours take the value of the central pixel.
+func gradients(tg *TileGrid, fn func(x, y int, dzdx, dzdy float64)) error {
+	if tg.Bands != 1 {
+		return fmt.Errorf("Elevation grid has %d bands instead of 1", tg.Bands)
+	}
+	z := tiling.NewZoomLevel(tg.Tile.Z).WithTileSize(tg.Size)
+	for y := 0; y < tg.Size; y++ {
+		lat := z.PixelToGeo(z.PixelOfTile(tg.Tile.X, tg.Tile.Y, tiling.Pixel{X: 0.5, Y: float64(y) + 0.5})).Lat
+		res := z.GroundResolution(lat)
+		for x := 0; x < tg.Size; x++ {
+			if tg.Missing(x, y) {
+				continue
+			}
+			e := tg.At(x, y)[0]
+			at := func(i, j int) float64 {
+				i, j = x+i, y+j
+				if i < 0 || j < 0 || i >= tg.Size || j >= tg.Size || tg.Missing(i, j) {
+					return e
+				}
+				return tg.At(i, j)[0]
+			}
+			a, b, c := at(-1, -1), at(0, -1), at(1, -1)
+			d, f := at(-1, 0), at(1, 0)
+			g, h, i := at(-1, 1), at(0, 1), at(1, 1)
+			dzdx := ((c + 2*f + i) - (a + 2*d + g)) / (8 * res)
+			dzdy := ((g + 2*h + i) - (a + 2*b + c)) / (8 * res)
+			fn(x, y, dzdx, dzdy)
+		}
+	}
+	return nil
+}
+
+//Slope gives the slope in degrees of every pixel of the single band elevation grid, missing pixels stay missing as NaN
+func Slope(tg *TileGrid) (*TileGrid, error) {
+	dst := NewTileGrid(tg.Tile, tg.Size, 1, math.NaN())
+	err := gradients(tg, func(x, y int, dzdx, dzdy float64) {
+		dst.At(x, y)[0] = math.Atan(math.Hypot(dzdx, dzdy)) * 180 / math.Pi
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+//Hillshade gives the illumination in [0, 255] of every pixel of the single band elevation grid lit from the given
+//azimuth, clockwise from north, and altitude above the horizon in degrees. Missing pixels stay missing as NaN.
+func Hillshade(tg *TileGrid, azimuth, altitude float64) (*TileGrid, error) {
+	az, alt := azimuth*math.Pi/180, altitude*math.Pi/180
+	lightE, lightN, lightU := math.Sin(az)*math.Cos(alt), math.Cos(az)*math.Cos(alt), math.Sin(alt)
+	dst := NewTileGrid(tg.Tile, tg.Size, 1, math.NaN())
+	err := gradients(tg, func(x, y int, dzdx, dzdy float64) {
+		//the surface normal is (-dz/dEast, -dz/dNorth, 1) and rows grow southward
+		shade := (-dzdx*lightE + dzdy*lightN + lightU) / math.Sqrt(dzdx*dzdx+dzdy*dzdy+1)
+		dst.At(x, y)[0] = 255 * math.Max(0, shade)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
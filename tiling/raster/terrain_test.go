@@ -0,0 +1,106 @@
+package raster_test
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/raster"
+)
+
+func TestElevationEncoding(t *testing.T) {
+	tl := tiling.Tile{X: 8529, Y: 5855, Z: 14}
+	heights := []float64{0, -10000, 8848.8, 123.4, -431.5}
+	encodings := []raster.Elevation{raster.TerrainRGB, raster.Terrarium}
+	zeros := []color.RGBA{{R: 1, G: 134, B: 160, A: 255}, {R: 128, G: 0, B: 0, A: 255}}
+	precisions := []float64{0.05, 1.0 / 256}
+	for e, enc := range encodings {
+		t.Run(fmt.Sprintf("Encoding %d", enc), func(t *testing.T) {
+			tg := raster.NewTileGrid(tl, 4, 1, math.NaN())
+			for i, h := range heights {
+				tg.At(i%4, i/4)[0] = h
+			}
+			img, err := raster.EncodeElevation(tg, enc)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if c := img.RGBAAt(0, 0); c != zeros[e] {
+				t.Errorf("Zero is encoded as %v instead of %v", c, zeros[e])
+			}
+			if c := img.RGBAAt(3, 3); c.A != 0 {
+				t.Errorf("Missing pixel should be transparent, got %v", c)
+			}
+			data, err := raster.EncodeElevationTile(tg, enc)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			dec, err := raster.DecodeElevationTile(tl, data, enc)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for i, h := range heights {
+				if v := dec.At(i%4, i/4)[0]; math.Abs(v-h) > precisions[e] {
+					t.Errorf("Height %f is decoded as %f", h, v)
+				}
+			}
+			if !dec.Missing(3, 3) || dec.Tile != tl {
+				t.Errorf("Unexpected decoded grid %+v", dec)
+			}
+		})
+	}
+	if _, err := raster.EncodeElevation(raster.NewTileGrid(tl, 4, 3, 0), raster.TerrainRGB); err == nil {
+		t.Errorf("A multi band grid should fail")
+	}
+}
+
+//planeGrid gives an elevation grid of the tile rising eastward with the given slope in degrees
+func planeGrid(tl tiling.Tile, size int, slope float64) *raster.TileGrid {
+	tg := raster.NewTileGrid(tl, size, 1, math.NaN())
+	res := tiling.NewZoomLevel(tl.Z).WithTileSize(size).GroundResolution(0)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			tg.At(x, y)[0] = float64(x) * res * math.Tan(slope*math.Pi/180)
+		}
+	}
+	return tg
+}
+
+func TestSlopeHillshade(t *testing.T) {
+	tl := tiling.Tile{X: 8192, Y: 8191, Z: 14}
+	slope, err := raster.Slope(planeGrid(tl, 64, 30))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, p := range [][2]int{{1, 1}, {32, 32}, {62, 62}} {
+		if s := slope.At(p[0], p[1])[0]; math.Abs(s-30) > 0.01 {
+			t.Errorf("Slope at %v is %f instead of 30", p, s)
+		}
+	}
+	flat, err := raster.Hillshade(planeGrid(tl, 64, 0), 315, 45)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if h := flat.At(10, 10)[0]; math.Abs(h-255*math.Sin(math.Pi/4)) > 1e-9 {
+		t.Errorf("Flat hillshade is %f", h)
+	}
+	east, _ := raster.Hillshade(planeGrid(tl, 64, 45), 90, 45)
+	west, _ := raster.Hillshade(planeGrid(tl, 64, 45), 270, 45)
+	if e, w := east.At(10, 10)[0], west.At(10, 10)[0]; math.Abs(e) > 1e-9 || math.Abs(w-255) > 1e-9 {
+		t.Errorf("A slope rising eastward should be dark lit from east and bright lit from west: %f %f", e, w)
+	}
+	high := tiling.Tile{X: 8192, Y: 2000, Z: 14}
+	hs, _ := raster.Slope(planeGrid(high, 64, 30))
+	lat := tiling.MercToGeo(tiling.ExtentOf(high).UL()).Lat
+	expected := math.Atan(math.Tan(30*math.Pi/180)/math.Cos(lat*math.Pi/180)) * 180 / math.Pi
+	if s := hs.At(32, 32)[0]; math.Abs(s-expected) > 0.05 {
+		t.Errorf("Slope at latitude %f is %f instead of %f", lat, s, expected)
+	}
+	missing := planeGrid(tl, 8, 10)
+	missing.At(4, 4)[0] = math.NaN()
+	ms, _ := raster.Slope(missing)
+	if !ms.Missing(4, 4) || ms.Missing(3, 4) {
+		t.Errorf("Missing pixels should stay missing")
+	}
+}
@@ -0,0 +1,222 @@
+package raster
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//GeoTransform is the affine transform from the continuous pixel coordinates of a grid to geo coordinates:
+//the pixel (x, y) is at Origin + x*Col + y*Row, where Col and Row are the geo steps of a column and a row
+type GeoTransform struct {
+	Origin tiling.PointG
+	Col    tiling.PointG
+	Row    tiling.PointG
+}
+
+//NorthUp gives the transform of a grid whose upper left corner is ul and whose pixels are lonStep by latStep degrees
+func NorthUp(ul tiling.PointG, lonStep, latStep float64) GeoTransform {
+	return GeoTransform{Origin: ul, Col: tiling.PointG{Lon: lonStep}, Row: tiling.PointG{Lat: -latStep}}
+}
+
+//Apply gives the geo point of the continuous pixel coordinates (x, y)
+func (gt GeoTransform) Apply(x, y float64) tiling.PointG {
+	return tiling.PointG{
+		Lon: gt.Origin.Lon + x*gt.Col.Lon + y*gt.Row.Lon,
+		Lat: gt.Origin.Lat + x*gt.Col.Lat + y*gt.Row.Lat,
+	}
+}
+
+//Inverse gives the continuous pixel coordinates of the geo point g, it fails if the transform is degenerate
+func (gt GeoTransform) Inverse(g tiling.PointG) (float64, float64, error) {
+	det := gt.Col.Lon*gt.Row.Lat - gt.Row.Lon*gt.Col.Lat
+	if det == 0 {
+		return 0, 0, fmt.Errorf("Degenerate geotransform %+v", gt)
+	}
+	dLon, dLat := g.Lon-gt.Origin.Lon, g.Lat-gt.Origin.Lat
+	return (dLon*gt.Row.Lat - dLat*gt.Row.Lon) / det, (gt.Col.Lon*dLat - gt.Col.Lat*dLon) / det, nil
+}
+
+//Grid is a georeferenced raster of float values, bands are interleaved by pixel row by row.
+//Pixels whose value is NoData in any band are missing, a nil NoData means all the pixels are valid.
+type Grid struct {
+	Width     int
+	Height    int
+	Bands     int
+	Data      []float64
+	Transform GeoTransform
+	NoData    *float64
+}
+
+//NewGrid creates a grid with all the values set to 0
+func NewGrid(width, height, bands int, gt GeoTransform) *Grid {
+	return &Grid{Width: width, Height: height, Bands: bands, Data: make([]float64, width*height*bands), Transform: gt}
+}
+
+//GridOfImage creates a grid with the RGBA bands of img, non premultiplied in [0, 255].
+//Transparent pixels are missing and get the value nodata.
+func GridOfImage(img image.Image, gt GeoTransform, nodata float64) *Grid {
+	b := img.Bounds()
+	g := NewGrid(b.Dx(), b.Dy(), 4, gt)
+	g.NoData = &nodata
+	i := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, gr, bl, a := img.At(x, y).RGBA()
+			if a == 0 {
+				g.Data[i], g.Data[i+1], g.Data[i+2], g.Data[i+3] = nodata, nodata, nodata, nodata
+			} else {
+				f := 255 / float64(a)
+				g.Data[i], g.Data[i+1], g.Data[i+2], g.Data[i+3] = float64(r)*f, float64(gr)*f, float64(bl)*f, float64(a)/0x101
+			}
+			i += 4
+		}
+	}
+	return g
+}
+
+//At gives the values of the pixel (x, y)
+func (g *Grid) At(x, y int) []float64 {
+	i := (y*g.Width + x) * g.Bands
+	return g.Data[i : i+g.Bands]
+}
+
+//missing tells if the values are missing
+func (g *Grid) missing(v []float64) bool {
+	if g.NoData == nil {
+		return false
+	}
+	for _, f := range v {
+		if f == *g.NoData || math.IsNaN(f) {
+			return true
+		}
+	}
+	return false
+}
+
+//TileGrid is a raster of float values covering a tile, bands are interleaved by pixel row by row.
+//Missing pixels have the value NoData, which may be NaN.
+type TileGrid struct {
+	Tile   tiling.Tile
+	Size   int
+	Bands  int
+	Data   []float64
+	NoData float64
+}
+
+//NewTileGrid creates a tile grid with all the values set to nodata
+func NewTileGrid(t tiling.Tile, size, bands int, nodata float64) *TileGrid {
+	tg := &TileGrid{Tile: t, Size: size, Bands: bands, Data: make([]float64, size*size*bands), NoData: nodata}
+	for i := range tg.Data {
+		tg.Data[i] = nodata
+	}
+	return tg
+}
+
+//At gives the values of the pixel (x, y)
+func (tg *TileGrid) At(x, y int) []float64 {
+	i := (y*tg.Size + x) * tg.Bands
+	return tg.Data[i : i+tg.Bands]
+}
+
+//Missing tells if the pixel (x, y) is missing
+func (tg *TileGrid) Missing(x, y int) bool {
+	for _, f := range tg.At(x, y) {
+		if f == tg.NoData || math.IsNaN(f) {
+			return true
+		}
+	}
+	return false
+}
+
+//Image converts a tile grid with 3 (RGB) or 4 (RGBA) bands in [0, 255] to an image, missing pixels are transparent
+func (tg *TileGrid) Image() (*image.RGBA, error) {
+	if tg.Bands != 3 && tg.Bands != 4 {
+		return nil, fmt.Errorf("Cannot convert %d bands to an image", tg.Bands)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, tg.Size, tg.Size))
+	v := make([]float64, 4)
+	for y := 0; y < tg.Size; y++ {
+		for x := 0; x < tg.Size; x++ {
+			if tg.Missing(x, y) {
+				continue
+			}
+			copy(v, tg.At(x, y))
+			if tg.Bands == 3 {
+				v[3] = 255
+			}
+			a := math.Max(0, math.Min(255, v[3])) / 255
+			setRGBA(img, x, y, []float64{v[0] / 255 * a, v[1] / 255 * a, v[2] / 255 * a, a})
+		}
+	}
+	return img, nil
+}
+
+//WarpOptions configures Warp: Size is the side of the tile in pixels, DefaultTileSize if zero
+type WarpOptions struct {
+	Size       int
+	Resampling Resampling
+}
+
+//Warp renders the grid in the XYZ tile t, inverse projecting the center of every tile pixel with MercToGeo.
+//Pixels outside the grid, or whose interpolation needs missing pixels and whose nearest pixel is missing, are missing.
+//The missing value is the NoData of the grid, or NaN.
+func Warp(src *Grid, t tiling.Tile, opts WarpOptions) (*TileGrid, error) {
+	if len(src.Data) != src.Width*src.Height*src.Bands {
+		return nil, fmt.Errorf("Grid has %d values instead of %dx%dx%d", len(src.Data), src.Width, src.Height, src.Bands)
+	}
+	size := opts.Size
+	if size <= 0 {
+		size = tiling.DefaultTileSize
+	}
+	nodata := math.NaN()
+	if src.NoData != nil {
+		nodata = *src.NoData
+	}
+	z := tiling.NewZoomLevel(t.Z).WithTileSize(size)
+	g := &grid{width: src.Width, height: src.Height, bands: src.Bands, data: src.Data}
+	dst := NewTileGrid(t, size, src.Bands, nodata)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			p := z.PixelOfTile(t.X, t.Y, tiling.Pixel{X: float64(i) + 0.5, Y: float64(j) + 0.5})
+			x, y, err := src.Transform.Inverse(tiling.MercToGeo(z.PixelToMerc(p)))
+			if err != nil {
+				return nil, err
+			}
+			if x < 0 || y < 0 || x >= float64(src.Width) || y >= float64(src.Height) {
+				continue
+			}
+			out := dst.At(i, j)
+			rs := opts.Resampling
+			if rs != Nearest && src.NoData != nil && !g.footprintValid(x, y, rs, src.missing) {
+				rs = Nearest
+			}
+			g.sample(x, y, rs, out)
+			if src.missing(out) {
+				for b := range out {
+					out[b] = nodata
+				}
+			}
+		}
+	}
+	return dst, nil
+}
+
+//footprintValid tells if all the pixels interpolated at (x, y) with the given resampling are valid
+func (g *grid) footprintValid(x, y float64, r Resampling, missing func([]float64) bool) bool {
+	lo, hi := 0, 1
+	if r == Cubic {
+		lo, hi = -1, 2
+	}
+	x0, y0 := int(math.Floor(x-0.5)), int(math.Floor(y-0.5))
+	for j := lo; j <= hi; j++ {
+		for i := lo; i <= hi; i++ {
+			if missing(g.pixel(x0+i, y0+j)) {
+				return false
+			}
+		}
+	}
+	return true
+}
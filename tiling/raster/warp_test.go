@@ -0,0 +1,140 @@
+package raster_test
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/raster"
+)
+
+func TestGeoTransform(t *testing.T) {
+	gt := raster.GeoTransform{
+		Origin: tiling.PointG{Lat: 46, Lon: 9},
+		Col:    tiling.PointG{Lat: -0.001, Lon: 0.01},
+		Row:    tiling.PointG{Lat: -0.01, Lon: 0.002},
+	}
+	for _, xy := range [][2]float64{{0, 0}, {10.5, 3.25}, {-4, 200}} {
+		x, y, err := gt.Inverse(gt.Apply(xy[0], xy[1]))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if math.Abs(x-xy[0]) > 1e-9 || math.Abs(y-xy[1]) > 1e-9 {
+			t.Errorf("Round trip of %v gives (%f, %f)", xy, x, y)
+		}
+	}
+	if g := raster.NorthUp(tiling.PointG{Lat: 46, Lon: 9}, 0.5, 0.25).Apply(2, 4); g.Lon != 10 || g.Lat != 45 {
+		t.Errorf("Unexpected north up point %+v", g)
+	}
+	if _, _, err := (raster.GeoTransform{}).Inverse(tiling.PointG{}); err == nil {
+		t.Errorf("A degenerate transform should fail")
+	}
+}
+
+//lonGrid gives a world grid of 1 degree pixels whose value is the longitude of the pixel center
+func lonGrid() *raster.Grid {
+	g := raster.NewGrid(360, 180, 1, raster.NorthUp(tiling.PointG{Lat: 90, Lon: -180}, 1, 1))
+	for y := 0; y < 180; y++ {
+		for x := 0; x < 360; x++ {
+			g.At(x, y)[0] = -180 + float64(x) + 0.5
+		}
+	}
+	return g
+}
+
+func TestWarp(t *testing.T) {
+	tl := tiling.Tile{X: 2, Y: 1, Z: 2}
+	z := tiling.NewZoomLevel(2).WithTileSize(64)
+	for _, rs := range []raster.Resampling{raster.Nearest, raster.Bilinear, raster.Cubic} {
+		tg, err := raster.Warp(lonGrid(), tl, raster.WarpOptions{Size: 64, Resampling: rs})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if tg.Size != 64 || tg.Bands != 1 || !math.IsNaN(tg.NoData) {
+			t.Fatalf("Unexpected tile grid %d %d %f", tg.Size, tg.Bands, tg.NoData)
+		}
+		for _, p := range [][2]int{{0, 0}, {10, 40}, {63, 63}} {
+			lon := z.PixelToGeo(z.PixelOfTile(tl.X, tl.Y, tiling.Pixel{X: float64(p[0]) + 0.5, Y: float64(p[1]) + 0.5})).Lon
+			expected := lon
+			if rs == raster.Nearest {
+				expected = math.Floor(lon) + 0.5
+			}
+			if v := tg.At(p[0], p[1])[0]; math.Abs(v-expected) > 1e-9 {
+				t.Errorf("Pixel %v with resampling %d is different (expected, actual) %f != %f", p, rs, expected, v)
+			}
+		}
+	}
+}
+
+func TestWarpNoData(t *testing.T) {
+	nodata := -9999.0
+	g := raster.NewGrid(4, 4, 1, raster.NorthUp(tiling.PointG{Lat: 45, Lon: 0}, 10, 10))
+	g.NoData = &nodata
+	for i := range g.Data {
+		g.Data[i] = 100
+	}
+	g.At(1, 1)[0] = nodata
+	tl := tiling.Tile{X: 2, Y: 1, Z: 2}
+	tg, err := raster.Warp(g, tl, raster.WarpOptions{Size: 256, Resampling: raster.Bilinear})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	z := tiling.NewZoomLevel(2)
+	pixelOf := func(lat, lon float64) (int, int) {
+		_, off := z.TileOfPixel(z.GeoToPixel(tiling.PointG{Lat: lat, Lon: lon}))
+		return int(off.X), int(off.Y)
+	}
+	checks := []struct {
+		lat, lon float64
+		missing  bool
+		value    float64
+	}{
+		{lat: 40, lon: 5, value: 100},
+		{lat: 30, lon: 15, missing: true},
+		{lat: 30, lon: 21, value: 100},
+		{lat: 10, lon: 35, value: 100},
+		{lat: 50, lon: 5, missing: true},
+		{lat: 40, lon: 45, missing: true},
+	}
+	for _, c := range checks {
+		x, y := pixelOf(c.lat, c.lon)
+		if m := tg.Missing(x, y); m != c.missing {
+			t.Errorf("Pixel of (%f, %f) missing is %t", c.lat, c.lon, m)
+		}
+		if v := tg.At(x, y)[0]; !c.missing && v != c.value {
+			t.Errorf("Pixel of (%f, %f) is %f instead of %f", c.lat, c.lon, v, c.value)
+		}
+	}
+	if _, err := raster.Warp(&raster.Grid{Width: 2, Height: 2, Bands: 1}, tl, raster.WarpOptions{}); err == nil {
+		t.Errorf("A grid without data should fail")
+	}
+}
+
+func TestWarpImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 360, 180))
+	for y := 0; y < 180; y++ {
+		for x := 0; x < 180; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	g := raster.GridOfImage(img, raster.NorthUp(tiling.PointG{Lat: 90, Lon: -180}, 1, 1), -1)
+	tg, err := raster.Warp(g, tiling.Tile{X: 0, Y: 0, Z: 0}, raster.WarpOptions{Resampling: raster.Cubic})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out, err := tg.Image()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c := out.RGBAAt(60, 128); c != (color.RGBA{R: 200, G: 100, B: 50, A: 255}) {
+		t.Errorf("Western pixel is %v", c)
+	}
+	if c := out.RGBAAt(200, 128); c.A != 0 {
+		t.Errorf("Eastern pixel should be transparent, got %v", c)
+	}
+	if _, err := raster.NewTileGrid(tiling.Tile{}, 4, 1, 0).Image(); err == nil {
+		t.Errorf("A single band should not convert to an image")
+	}
+}
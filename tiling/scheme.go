@@ -0,0 +1,65 @@
+package tiling
+
+import "fmt"
+
+//Scheme is the tile addressing convention used to number the rows of a zoom level
+type Scheme int
+
+const (
+	//XYZ is the Google/OSM convention: row 0 is the northernmost one (origin top-left)
+	XYZ Scheme = iota
+	//TMS is the OSGeo Tile Map Service convention: row 0 is the southernmost one (origin bottom-left)
+	TMS
+	//WMTS is the OGC TileMatrix convention of the GoogleMapsCompatible set: TileRow 0 is the northernmost one
+	//and TileMatrix is the zoom level, so the rows are the same of XYZ
+	WMTS
+)
+
+func (s Scheme) String() string {
+	switch s {
+	case XYZ:
+		return "xyz"
+	case TMS:
+		return "tms"
+	case WMTS:
+		return "wmts"
+	default:
+		return fmt.Sprintf("Scheme(%d)", int(s))
+	}
+}
+
+//ParseScheme gives the scheme with the given name (xyz, tms or wmts)
+func ParseScheme(name string) (Scheme, error) {
+	for _, s := range []Scheme{XYZ, TMS, WMTS} {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return XYZ, fmt.Errorf("Unknown tiling scheme %q", name)
+}
+
+//topLeft tells if the rows of the scheme are numbered from the north
+func (s Scheme) topLeft() bool {
+	return s != TMS
+}
+
+//FlipY converts a row number between the top-left and the bottom-left conventions at zoom z
+func FlipY(y, z int) int {
+	return (1 << uint(z)) - 1 - y
+}
+
+//ConvertTile gives the same tile addressed in the scheme to, starting from the scheme from
+func ConvertTile(t Tile, from, to Scheme) Tile {
+	if from.topLeft() != to.topLeft() {
+		t.Y = FlipY(t.Y, t.Z)
+	}
+	return t
+}
+
+//ConvertRange gives the same range addressed in the scheme to, starting from the scheme from
+func ConvertRange(r Range, from, to Scheme) Range {
+	if from.topLeft() != to.topLeft() {
+		r.MinY, r.MaxY = FlipY(r.MaxY, r.ZL), FlipY(r.MinY, r.ZL)
+	}
+	return r
+}
@@ -0,0 +1,84 @@
+package tiling_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestConvertTile(t *testing.T) {
+	tiles := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 33, Y: 23, Z: 6},
+		tiling.Tile{X: 5, Y: 8, Z: 4},
+		tiling.Tile{X: 106960, Y: 75432, Z: 17},
+	}
+	tms := []tiling.Tile{
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 33, Y: 40, Z: 6},
+		tiling.Tile{X: 5, Y: 7, Z: 4},
+		tiling.Tile{X: 106960, Y: 55639, Z: 17},
+	}
+	for i, tl := range tiles {
+		t.Run(fmt.Sprintf("Tile %d (X,Y,Z)(%d, %d, %d)", i, tl.X, tl.Y, tl.Z), func(t *testing.T) {
+			c := tiling.ConvertTile(tl, tiling.XYZ, tiling.TMS)
+			if c != tms[i] {
+				t.Errorf("TMS tile is different (expected, actual) %+v != %+v", tms[i], c)
+			}
+			if b := tiling.ConvertTile(c, tiling.TMS, tiling.XYZ); b != tl {
+				t.Errorf("Back conversion is different (expected, actual) %+v != %+v", tl, b)
+			}
+			if w := tiling.ConvertTile(tl, tiling.XYZ, tiling.WMTS); w != tl {
+				t.Errorf("WMTS tile is different (expected, actual) %+v != %+v", tl, w)
+			}
+		})
+	}
+}
+
+func TestZoomLevelScheme(t *testing.T) {
+	mercs := []tiling.PointM{
+		tiling.PointM{E: 910763.1357121654, N: 5309377.085697312},
+		tiling.PointM{E: -6514065.628545966, N: -259688.542848654},
+		tiling.PointM{E: 12665509.838740565, N: -3025789.0757535584},
+	}
+	zooms := []int{6, 4, 17}
+	for i, p := range mercs {
+		t.Run(fmt.Sprintf("Point %d (E,N)(%f, %f)", i, p.E, p.N), func(t *testing.T) {
+			xyz := tiling.NewZoomLevel(zooms[i])
+			tms := xyz.WithScheme(tiling.TMS)
+			if xyz.Scheme() != tiling.XYZ || tms.Scheme() != tiling.TMS {
+				t.Fatalf("Wrong schemes %v %v", xyz.Scheme(), tms.Scheme())
+			}
+			tx := xyz.TileOfMerc(p)
+			tt := tms.TileOfMerc(p)
+			if tt != tiling.ConvertTile(tx, tiling.XYZ, tiling.TMS) {
+				t.Errorf("TMS tile %+v does not match XYZ tile %+v", tt, tx)
+			}
+			if !tiling.Equals(xyz.ExtentOfTile(tx.X, tx.Y), tms.ExtentOfTile(tt.X, tt.Y)) {
+				t.Errorf("Extents of the same tile differ between schemes")
+			}
+			ext := tiling.ExtentM{West: p.E - 1000000, East: p.E, South: p.N - 1000000, North: p.N}
+			rx := xyz.RangeOf(ext)
+			rt := tms.RangeOf(ext)
+			if rt.MinY > rt.MaxY || rt != tiling.ConvertRange(rx, tiling.XYZ, tiling.TMS) {
+				t.Errorf("TMS range %+v does not match XYZ range %+v", rt, rx)
+			}
+			if xyz.RangeCardinality(ext) != tms.RangeCardinality(ext) {
+				t.Errorf("Range cardinality differs between schemes")
+			}
+		})
+	}
+}
+
+func TestParseScheme(t *testing.T) {
+	for _, s := range []tiling.Scheme{tiling.XYZ, tiling.TMS, tiling.WMTS} {
+		p, err := tiling.ParseScheme(s.String())
+		if err != nil || p != s {
+			t.Errorf("Parsing %q gave %v, %v", s.String(), p, err)
+		}
+	}
+	if _, err := tiling.ParseScheme("google"); err == nil {
+		t.Errorf("Unknown scheme should fail")
+	}
+}
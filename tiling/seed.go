@@ -0,0 +1,284 @@
+package tiling
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+//SeedLevel is the part of a SeedPlan at a single zoom level
+type SeedLevel struct {
+	Zoom   int
+	Ranges []Range
+	Count  int64
+}
+
+//SeedPlan describes the XYZ tiles to generate for an area over a zoom range.
+//Tiles of each level are kept as non overlapping ranges, so the plan stays small at deep zoom levels.
+type SeedPlan struct {
+	MinZoom int
+	MaxZoom int
+	Levels  []SeedLevel
+}
+
+//SeedJob is a batch of tiles of a single zoom level to be generated by a worker.
+//Jobs are numbered from 0 in the order they are produced by SeedPlan.EachJob.
+type SeedJob struct {
+	ID     int
+	Zoom   int
+	Ranges []Range
+	Count  int64
+}
+
+//Checkpoint records the completed jobs of a plan so an interrupted seeding can be resumed.
+//Every job with an ID lower than Watermark is done, Done lists the other completed jobs.
+//Fingerprint identifies the plan and the batch size the jobs were cut with.
+//It is not safe for concurrent use.
+type Checkpoint struct {
+	BatchSize   int64  `json:"batch_size"`
+	Total       int64  `json:"total"`
+	Fingerprint string `json:"fingerprint"`
+	Watermark   int    `json:"watermark"`
+	Done        []int  `json:"done,omitempty"`
+}
+
+//PlanExtent plans the tiles covering the given geo extent, which may cross the antimeridian,
+//from minZoom to maxZoom included
+func PlanExtent(ext ExtentG, minZoom, maxZoom int) (*SeedPlan, error) {
+	if err := checkZoomRange(minZoom, maxZoom); err != nil {
+		return nil, err
+	}
+	ext.MinLat = clampLat(ext.MinLat)
+	ext.MaxLat = clampLat(ext.MaxLat)
+	merc := GeoToMercExt(ext)
+	return newSeedPlan(minZoom, maxZoom, func(z *ZoomLevel) []Range {
+		return z.RangesOf(merc)
+	}), nil
+}
+
+//PlanPolygons plans the tiles covering the given geo polygons with the given coverage mode,
+//from minZoom to maxZoom included. Polygons must not cross the antimeridian.
+func PlanPolygons(polys []PolygonG, mode Coverage, minZoom, maxZoom int) (*SeedPlan, error) {
+	if err := checkZoomRange(minZoom, maxZoom); err != nil {
+		return nil, err
+	}
+	merc := make([]PolygonM, len(polys))
+	for i, p := range polys {
+		merc[i] = GeoToMercPolygon(p)
+	}
+	return newSeedPlan(minZoom, maxZoom, func(z *ZoomLevel) []Range {
+		return z.PolygonRanges(merc, mode)
+	}), nil
+}
+
+func checkZoomRange(minZoom, maxZoom int) error {
+	if minZoom < 0 || maxZoom > maxCurveZoom || minZoom > maxZoom {
+		return fmt.Errorf("Invalid zoom range: %d-%d", minZoom, maxZoom)
+	}
+	return nil
+}
+
+func clampLat(lat float64) float64 {
+	if lat > tileMaxLat {
+		return tileMaxLat
+	}
+	if lat < tileMinLat {
+		return tileMinLat
+	}
+	return lat
+}
+
+func newSeedPlan(minZoom, maxZoom int, cover func(*ZoomLevel) []Range) *SeedPlan {
+	p := &SeedPlan{MinZoom: minZoom, MaxZoom: maxZoom}
+	for z := minZoom; z <= maxZoom; z++ {
+		ranges := cover(NewZoomLevel(z))
+		sort.Slice(ranges, func(i, j int) bool {
+			if ranges[i].MinY != ranges[j].MinY {
+				return ranges[i].MinY < ranges[j].MinY
+			}
+			return ranges[i].MinX < ranges[j].MinX
+		})
+		l := SeedLevel{Zoom: z, Ranges: ranges}
+		for _, r := range ranges {
+			l.Count += r.Cardinality()
+		}
+		p.Levels = append(p.Levels, l)
+	}
+	return p
+}
+
+//Count gives the number of tiles planned at zoom level z
+func (p *SeedPlan) Count(z int) int64 {
+	for _, l := range p.Levels {
+		if l.Zoom == z {
+			return l.Count
+		}
+	}
+	return 0
+}
+
+//Total gives the number of tiles of the plan
+func (p *SeedPlan) Total() int64 {
+	var total int64
+	for _, l := range p.Levels {
+		total += l.Count
+	}
+	return total
+}
+
+//EstimateSize gives the storage in bytes needed by the plan given the average size of a tile at each zoom level,
+//bytesPerTile may be nil to use avgBytes for every level
+func (p *SeedPlan) EstimateSize(avgBytes int64, bytesPerTile map[int]int64) int64 {
+	var size int64
+	for _, l := range p.Levels {
+		b, ok := bytesPerTile[l.Zoom]
+		if !ok {
+			b = avgBytes
+		}
+		size += l.Count * b
+	}
+	return size
+}
+
+//EachJob calls fn for every job of at most batchSize tiles until fn returns false.
+//Jobs follow the zoom levels from the lowest, and each level its ranges row by row,
+//so the same plan and batch size always produce the same jobs.
+func (p *SeedPlan) EachJob(batchSize int64, fn func(SeedJob) bool) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("Invalid batch size: %d", batchSize)
+	}
+	id := 0
+	for _, l := range p.Levels {
+		job := SeedJob{Zoom: l.Zoom}
+		emit := func() bool {
+			job.ID = id
+			id++
+			ok := fn(job)
+			job = SeedJob{Zoom: l.Zoom}
+			return ok
+		}
+		for _, r := range l.Ranges {
+			ok := eachBatchPiece(r, batchSize, func(piece Range) bool {
+				if job.Count+piece.Cardinality() > batchSize && !emit() {
+					return false
+				}
+				job.Ranges = append(job.Ranges, piece)
+				job.Count += piece.Cardinality()
+				return true
+			})
+			if !ok {
+				return nil
+			}
+		}
+		if job.Count > 0 && !emit() {
+			return nil
+		}
+	}
+	return nil
+}
+
+//Jobs gives all the jobs of at most batchSize tiles, see EachJob
+func (p *SeedPlan) Jobs(batchSize int64) ([]SeedJob, error) {
+	var jobs []SeedJob
+	err := p.EachJob(batchSize, func(j SeedJob) bool {
+		jobs = append(jobs, j)
+		return true
+	})
+	return jobs, err
+}
+
+//eachBatchPiece cuts the range in blocks of whole rows, or in pieces of a row when a row exceeds batchSize,
+//and calls fn for each of them until fn returns false
+func eachBatchPiece(r Range, batchSize int64, fn func(Range) bool) bool {
+	if r.Cardinality() <= batchSize {
+		return fn(r)
+	}
+	w := int64(r.Width())
+	if w > batchSize {
+		for y := r.MinY; y <= r.MaxY; y++ {
+			for x := int64(r.MinX); x <= int64(r.MaxX); x += batchSize {
+				maxX := x + batchSize - 1
+				if maxX > int64(r.MaxX) {
+					maxX = int64(r.MaxX)
+				}
+				if !fn(Range{MinX: int(x), MaxX: int(maxX), MinY: y, MaxY: y, ZL: r.ZL}) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	rows := int(batchSize / w)
+	for y := r.MinY; y <= r.MaxY; y += rows {
+		if !fn(Range{MinX: r.MinX, MaxX: r.MaxX, MinY: y, MaxY: minInt(y+rows-1, r.MaxY), ZL: r.ZL}) {
+			return false
+		}
+	}
+	return true
+}
+
+//NewCheckpoint creates an empty checkpoint for the jobs of at most batchSize tiles of the plan
+func (p *SeedPlan) NewCheckpoint(batchSize int64) Checkpoint {
+	return Checkpoint{BatchSize: batchSize, Total: p.Total(), Fingerprint: p.fingerprint(batchSize)}
+}
+
+//fingerprint gives a hash of the zoom range, of the ranges of every level and of the batch size,
+//which together determine the jobs
+func (p *SeedPlan) fingerprint(batchSize int64) string {
+	h := sha1.New()
+	write := func(vs ...int64) {
+		for _, v := range vs {
+			binary.Write(h, binary.BigEndian, v)
+		}
+	}
+	write(int64(p.MinZoom), int64(p.MaxZoom), batchSize)
+	for _, l := range p.Levels {
+		write(int64(l.Zoom), int64(len(l.Ranges)))
+		for _, r := range l.Ranges {
+			write(int64(r.MinX), int64(r.MaxX), int64(r.MinY), int64(r.MaxY))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+//Resume calls fn for every job of the plan not yet completed according to the checkpoint, until fn returns false.
+//Jobs are cut with the batch size of the checkpoint, it fails if the checkpoint was created for a different plan.
+func (p *SeedPlan) Resume(c Checkpoint, fn func(SeedJob) bool) error {
+	if c.Fingerprint != p.fingerprint(c.BatchSize) {
+		return fmt.Errorf("Checkpoint does not match the plan: %d tiles, %d per job, fingerprint %q", c.Total, c.BatchSize, c.Fingerprint)
+	}
+	return p.EachJob(c.BatchSize, func(j SeedJob) bool {
+		if c.IsDone(j.ID) {
+			return true
+		}
+		return fn(j)
+	})
+}
+
+//IsDone tells if the job with the given ID is completed
+func (c *Checkpoint) IsDone(id int) bool {
+	if id < c.Watermark {
+		return true
+	}
+	i := sort.SearchInts(c.Done, id)
+	return i < len(c.Done) && c.Done[i] == id
+}
+
+//MarkDone records the job with the given ID as completed, advancing the watermark over contiguous completed jobs
+func (c *Checkpoint) MarkDone(id int) {
+	if c.IsDone(id) {
+		return
+	}
+	i := sort.SearchInts(c.Done, id)
+	c.Done = append(c.Done, 0)
+	copy(c.Done[i+1:], c.Done[i:])
+	c.Done[i] = id
+	n := 0
+	for n < len(c.Done) && c.Done[n] == c.Watermark {
+		c.Watermark++
+		n++
+	}
+	c.Done = c.Done[n:]
+}
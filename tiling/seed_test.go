@@ -0,0 +1,202 @@
+package tiling_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestPlanExtentCounts(t *testing.T) {
+	world := tiling.ExtentG{MinLon: -180, MaxLon: 180, MinLat: -90, MaxLat: 90}
+	p, err := tiling.PlanExtent(world, 0, 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for z := 0; z <= 20; z++ {
+		if c := p.Count(z); c != int64(1)<<uint(2*z) {
+			t.Errorf("Count at zoom %d is different (expected, actual) %d != %d", z, int64(1)<<uint(2*z), c)
+		}
+	}
+	if total := p.Total(); total != 1466015503701 {
+		t.Errorf("Total is different (expected, actual) %d != %d", int64(1466015503701), total)
+	}
+	if size := p.EstimateSize(10, map[int]int64{0: 1000}); size != (1466015503701-1)*10+1000 {
+		t.Errorf("Unexpected size estimation %d", size)
+	}
+	fiji := tiling.ExtentG{MinLon: 177, MaxLon: -178, MinLat: -19, MaxLat: -16}
+	p, err = tiling.PlanExtent(fiji, 6, 12)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for z := 6; z <= 12; z++ {
+		expected := tiling.NewZoomLevel(z).RangesCardinality(tiling.GeoToMercExt(fiji))
+		if c := p.Count(z); c != expected {
+			t.Errorf("Count at zoom %d is different (expected, actual) %d != %d", z, expected, c)
+		}
+		if n := len(p.Levels[z-6].Ranges); n != 2 {
+			t.Errorf("Expected a range for each side of the antimeridian, got %d", n)
+		}
+	}
+	for _, zr := range [][2]int{{-1, 3}, {5, 4}, {0, 32}} {
+		if _, err := tiling.PlanExtent(world, zr[0], zr[1]); err == nil {
+			t.Errorf("Zoom range %v should fail", zr)
+		}
+	}
+}
+
+func TestPlanPolygons(t *testing.T) {
+	poly := tiling.PolygonG{{{Lon: 10, Lat: 40}, {Lon: 14, Lat: 40}, {Lon: 12, Lat: 45}}}
+	for _, mode := range []tiling.Coverage{tiling.Intersecting, tiling.Contained} {
+		p, err := tiling.PlanPolygons([]tiling.PolygonG{poly}, mode, 4, 11)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, l := range p.Levels {
+			tiles := tiling.NewZoomLevel(l.Zoom).PolygonTiles([]tiling.PolygonM{tiling.GeoToMercPolygon(poly)}, mode)
+			planned := []tiling.Tile{}
+			for _, r := range l.Ranges {
+				r.Each(func(tl tiling.Tile) bool {
+					planned = append(planned, tl)
+					return true
+				})
+			}
+			if l.Count != int64(len(tiles)) || !reflect.DeepEqual(planned, tiles) {
+				t.Errorf("Planned tiles at zoom %d differ from the polygon tiles: %d != %d", l.Zoom, l.Count, len(tiles))
+			}
+		}
+	}
+}
+
+func TestPlanPolygonsDeepZoom(t *testing.T) {
+	ext := tiling.ExtentG{MinLon: 6.3, MaxLon: 18.7, MinLat: 36.4, MaxLat: 47.2}
+	rect := tiling.PolygonG{{
+		{Lon: ext.MinLon, Lat: ext.MinLat}, {Lon: ext.MaxLon, Lat: ext.MinLat},
+		{Lon: ext.MaxLon, Lat: ext.MaxLat}, {Lon: ext.MinLon, Lat: ext.MaxLat},
+	}}
+	const zoom = 18
+	full, err := tiling.PlanExtent(ext, zoom, zoom)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	r := full.Levels[0].Ranges[0]
+	w, h := int64(r.MaxX-r.MinX+1), int64(r.MaxY-r.MinY+1)
+	for _, c := range []struct {
+		mode  tiling.Coverage
+		count int64
+		rows  int64
+	}{
+		{tiling.Intersecting, w * h, h},
+		{tiling.Contained, (w - 2) * (h - 2), h - 2},
+	} {
+		p, err := tiling.PlanPolygons([]tiling.PolygonG{rect}, c.mode, zoom, zoom)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		l := p.Levels[0]
+		if l.Count != c.count {
+			t.Errorf("Count of mode %d is different (expected, actual) %d != %d", c.mode, c.count, l.Count)
+		}
+		if int64(len(l.Ranges)) != c.rows {
+			t.Errorf("Expected a single range per row, got %d ranges for %d rows", len(l.Ranges), c.rows)
+		}
+	}
+	tri := tiling.PolygonG{{{Lon: ext.MinLon, Lat: ext.MinLat}, {Lon: ext.MaxLon, Lat: ext.MinLat}, {Lon: ext.MinLon, Lat: ext.MaxLat}}}
+	p, err := tiling.PlanPolygons([]tiling.PolygonG{tri}, tiling.Intersecting, zoom, zoom)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if l := p.Levels[0]; l.Count <= w*h/2 || l.Count >= w*h*3/5 || len(l.Ranges) != int(h) {
+		t.Errorf("Unexpected triangle plan: %d tiles in %d ranges, extent of %d tiles in %d rows", l.Count, len(l.Ranges), w*h, h)
+	}
+}
+
+func TestSeedJobs(t *testing.T) {
+	ext := tiling.ExtentG{MinLon: 5, MaxLon: 20, MinLat: 36, MaxLat: 48}
+	p, err := tiling.PlanExtent(ext, 3, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, batch := range []int64{1, 7, 100, 5000, 1 << 40} {
+		t.Run(fmt.Sprintf("Batch %d", batch), func(t *testing.T) {
+			jobs, err := p.Jobs(batch)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			seen := make(map[tiling.Tile]bool)
+			for i, j := range jobs {
+				if j.ID != i || j.Count > batch || j.Count == 0 {
+					t.Errorf("Invalid job %d: %+v", i, j)
+				}
+				var count int64
+				for _, r := range j.Ranges {
+					count += r.Cardinality()
+					r.Each(func(tl tiling.Tile) bool {
+						if tl.Z != j.Zoom || seen[tl] {
+							t.Errorf("Tile %+v is repeated or in the wrong job", tl)
+						}
+						seen[tl] = true
+						return true
+					})
+				}
+				if count != j.Count {
+					t.Errorf("Job %d count is different (expected, actual) %d != %d", i, count, j.Count)
+				}
+			}
+			if int64(len(seen)) != p.Total() {
+				t.Errorf("Jobs cover %d tiles instead of %d", len(seen), p.Total())
+			}
+			again, _ := p.Jobs(batch)
+			if !reflect.DeepEqual(jobs, again) {
+				t.Errorf("Jobs are not deterministic")
+			}
+		})
+	}
+	if _, err := p.Jobs(0); err == nil {
+		t.Errorf("A zero batch size should fail")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	ext := tiling.ExtentG{MinLon: 5, MaxLon: 20, MinLat: 36, MaxLat: 48}
+	p, _ := tiling.PlanExtent(ext, 3, 8)
+	jobs, _ := p.Jobs(50)
+	c := p.NewCheckpoint(50)
+	for _, id := range []int{2, 0, 5, 1} {
+		c.MarkDone(id)
+	}
+	if c.Watermark != 3 || !reflect.DeepEqual(c.Done, []int{5}) {
+		t.Errorf("Unexpected checkpoint state %+v", c)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var restored tiling.Checkpoint
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var resumed []int
+	if err := p.Resume(restored, func(j tiling.SeedJob) bool {
+		resumed = append(resumed, j.ID)
+		return true
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resumed) != len(jobs)-4 || resumed[0] != 3 || resumed[1] != 4 || resumed[2] != 6 {
+		t.Errorf("Unexpected resumed jobs %v of %d", resumed, len(jobs))
+	}
+	other, _ := tiling.PlanExtent(ext, 3, 9)
+	if err := other.Resume(restored, func(tiling.SeedJob) bool { return true }); err == nil {
+		t.Errorf("A checkpoint of a different plan should fail")
+	}
+	shifted, _ := tiling.PlanExtent(tiling.ExtentG{MinLon: 95, MaxLon: 110, MinLat: 36, MaxLat: 48}, 3, 8)
+	if shifted.Total() != p.Total() {
+		t.Fatalf("Shifted plan should have the same tiles, %d != %d", p.Total(), shifted.Total())
+	}
+	if err := shifted.Resume(restored, func(tiling.SeedJob) bool { return true }); err == nil {
+		t.Errorf("A checkpoint of a different plan with the same tiles should fail")
+	}
+}
@@ -0,0 +1,228 @@
+//Package server serves the tiles of a store.Store over HTTP as /{z}/{x}/{y}.{ext}, with a TileJSON description
+package server
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/store"
+)
+
+//TileJSONPath is the path, relative to the handler, of the TileJSON document
+const TileJSONPath = "/tile.json"
+
+//DefaultCacheControl is the Cache-Control header of the tiles when not given in the Options
+const DefaultCacheControl = "public, max-age=86400"
+
+//Options configure a Handler
+type Options struct {
+	//Ext is the extension of the served tiles, without the dot
+	Ext string
+	//ContentType of the tiles, guessed from Ext when empty
+	ContentType string
+	//CacheControl header of the tiles, DefaultCacheControl when empty
+	CacheControl string
+	//Bounds and the zoom range limit the served tiles, tiles outside them are not found.
+	//Bounds are read from the store when empty, the zoom range when both MinZoom and MaxZoom are 0.
+	Bounds  tiling.ExtentG
+	MinZoom int
+	MaxZoom int
+	//Name and Attribution are reported in the TileJSON document
+	Name        string
+	Attribution string
+	//BaseURL is the URL the handler is reachable at, used in the TileJSON tiles template.
+	//When empty it is derived from the TileJSON request.
+	BaseURL string
+}
+
+//Handler is an http.Handler serving the tiles of a store
+type Handler struct {
+	store store.Store
+	opts  Options
+	//bounds are the Bounds of the options in mercator
+	bounds tiling.ExtentM
+}
+
+//NewHandler creates a handler serving the tiles of s, when opts.Bounds or the zoom range are empty they are read
+//from the store with store.Describe, which scans all the tiles of the stores that are not a store.Describer
+func NewHandler(s store.Store, opts Options) (*Handler, error) {
+	if opts.ContentType == "" {
+		opts.ContentType = mime.TypeByExtension("." + opts.Ext)
+		if opts.Ext == "pbf" || opts.Ext == "mvt" {
+			opts.ContentType = "application/vnd.mapbox-vector-tile"
+		}
+		if opts.ContentType == "" {
+			opts.ContentType = "application/octet-stream"
+		}
+	}
+	if opts.CacheControl == "" {
+		opts.CacheControl = DefaultCacheControl
+	}
+	noBounds, noZooms := opts.Bounds == (tiling.ExtentG{}), opts.MinZoom == 0 && opts.MaxZoom == 0
+	if noBounds || noZooms {
+		bounds, minZoom, maxZoom, err := store.Describe(s)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot describe the store: %v", err)
+		}
+		if noBounds {
+			opts.Bounds = bounds
+		}
+		if noZooms {
+			opts.MinZoom, opts.MaxZoom = minZoom, maxZoom
+		}
+	}
+	if opts.MinZoom < 0 || opts.MaxZoom < opts.MinZoom {
+		return nil, fmt.Errorf("Invalid zoom range [%d, %d]", opts.MinZoom, opts.MaxZoom)
+	}
+	return &Handler{store: s, opts: opts, bounds: tiling.GeoToMercExt(opts.Bounds)}, nil
+}
+
+//ServeHTTP serves a tile or the TileJSON document
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.URL.Path == TileJSONPath {
+		h.serveTileJSON(w, r)
+		return
+	}
+	t, err := h.parseTile(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	data, err := h.store.Get(t)
+	if err == store.ErrTileNotFound {
+		http.Error(w, "Tile not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Cannot read tile", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", h.opts.CacheControl)
+	if len(data) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	sum := sha1.Sum(data)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	w.Header().Set("ETag", etag)
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", h.opts.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(data)
+}
+
+//parseTile reads the tile of a /{z}/{x}/{y}.{ext} path and checks it against the zoom level, the served zoom range
+//and the served bounds
+func (h *Handler) parseTile(p string) (tiling.Tile, error) {
+	parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	if len(parts) != 3 {
+		return tiling.Tile{}, fmt.Errorf("Invalid tile path %q", p)
+	}
+	last := parts[2]
+	if h.opts.Ext != "" {
+		if !strings.HasSuffix(last, "."+h.opts.Ext) {
+			return tiling.Tile{}, fmt.Errorf("Invalid tile extension in %q", p)
+		}
+		last = strings.TrimSuffix(last, "."+h.opts.Ext)
+	}
+	var v [3]int
+	for i, s := range []string{parts[0], parts[1], last} {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return tiling.Tile{}, fmt.Errorf("Invalid tile path %q", p)
+		}
+		v[i] = n
+	}
+	t := tiling.Tile{Z: v[0], X: v[1], Y: v[2]}
+	if t.Z < h.opts.MinZoom || t.Z > h.opts.MaxZoom {
+		return t, fmt.Errorf("Zoom %d out of range [%d, %d]", t.Z, h.opts.MinZoom, h.opts.MaxZoom)
+	}
+	size := 1 << uint(t.Z)
+	if t.X < 0 || t.Y < 0 || t.X >= size || t.Y >= size {
+		return t, fmt.Errorf("Tile (x, y, z)(%d, %d, %d) out of the zoom level", t.X, t.Y, t.Z)
+	}
+	if len(tiling.Intersections(tiling.ExtentOf(t), h.bounds)) == 0 {
+		return t, fmt.Errorf("Tile (x, y, z)(%d, %d, %d) out of the bounds", t.X, t.Y, t.Z)
+	}
+	return t, nil
+}
+
+//matchETag tells if the If-None-Match header matches the given entity tag
+func matchETag(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+//TileJSON is a TileJSON 3.0.0 document https://github.com/mapbox/tilejson-spec/tree/master/3.0.0
+type TileJSON struct {
+	TileJSON    string    `json:"tilejson"`
+	Tiles       []string  `json:"tiles"`
+	Name        string    `json:"name,omitempty"`
+	Attribution string    `json:"attribution,omitempty"`
+	Scheme      string    `json:"scheme"`
+	MinZoom     int       `json:"minzoom"`
+	MaxZoom     int       `json:"maxzoom"`
+	Bounds      []float64 `json:"bounds"`
+	Center      []float64 `json:"center"`
+}
+
+//TileJSON gives the TileJSON document of the handler, with tiles served at baseURL
+func (h *Handler) TileJSON(baseURL string) TileJSON {
+	tiles := strings.TrimSuffix(baseURL, "/") + "/{z}/{x}/{y}"
+	if h.opts.Ext != "" {
+		tiles += "." + h.opts.Ext
+	}
+	b := h.opts.Bounds
+	return TileJSON{
+		TileJSON:    "3.0.0",
+		Tiles:       []string{tiles},
+		Name:        h.opts.Name,
+		Attribution: h.opts.Attribution,
+		Scheme:      tiling.XYZ.String(),
+		MinZoom:     h.opts.MinZoom,
+		MaxZoom:     h.opts.MaxZoom,
+		Bounds:      []float64{b.MinLon, b.MinLat, b.MaxLon, b.MaxLat},
+		Center:      []float64{(b.MinLon + b.MaxLon) / 2, (b.MinLat + b.MaxLat) / 2, float64(h.opts.MinZoom)},
+	}
+}
+
+func (h *Handler) serveTileJSON(w http.ResponseWriter, r *http.Request) {
+	base := h.opts.BaseURL
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		reqPath := r.URL.Path
+		if r.RequestURI != "" {
+			reqPath = strings.SplitN(r.RequestURI, "?", 2)[0]
+		}
+		base = scheme + "://" + r.Host + path.Dir(reqPath)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.TileJSON(base))
+}
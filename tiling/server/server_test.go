@@ -0,0 +1,159 @@
+package server_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/server"
+	"github.com/trealtamira/gopkgs/tiling/store"
+)
+
+func newStore(t *testing.T) store.Store {
+	dir, err := ioutil.TempDir("", "server")
+	if err != nil {
+		t.Fatalf("Cannot create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	s := store.NewFS(dir, store.FSOptions{Ext: "png"})
+	s.Put(tiling.Tile{X: 1, Y: 1, Z: 2}, []byte("tile"))
+	s.Put(tiling.Tile{X: 2, Y: 1, Z: 2}, []byte{})
+	s.Put(tiling.Tile{X: 4, Y: 3, Z: 3}, []byte("deep"))
+	return s
+}
+
+func TestServeTiles(t *testing.T) {
+	h, err := server.NewHandler(newStore(t), server.Options{Ext: "png"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	paths := []string{"/2/1/1.png", "/2/2/1.png", "/2/0/0.png", "/2/4/0.png", "/1/0/0.png", "/2/1/1.jpg", "/2/a/1.png", "/2/1"}
+	codes := []int{200, 204, 404, 404, 404, 404, 404, 404}
+	for i, p := range paths {
+		t.Run(fmt.Sprintf("GET %s", p), func(t *testing.T) {
+			resp, err := http.Get(srv.URL + p)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != codes[i] {
+				t.Errorf("Status is different (expected, actual) %d != %d", codes[i], resp.StatusCode)
+			}
+			if resp.StatusCode == 200 {
+				body, _ := ioutil.ReadAll(resp.Body)
+				if string(body) != "tile" || resp.Header.Get("Content-Type") != "image/png" ||
+					resp.Header.Get("Cache-Control") != server.DefaultCacheControl || resp.Header.Get("ETag") == "" {
+					t.Errorf("Unexpected response %q %+v", body, resp.Header)
+				}
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	h, err := server.NewHandler(newStore(t), server.Options{Ext: "png", CacheControl: "no-cache"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/2/1/1.png", nil))
+	etag := rec.Header().Get("ETag")
+	if rec.Code != 200 || etag == "" || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("Unexpected response %d %+v", rec.Code, rec.Header())
+	}
+	req := httptest.NewRequest("GET", "/2/1/1.png", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("Expected 304, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/2/1/1.png", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("HEAD", "/2/1/1.png", nil))
+	if rec.Code != 200 || rec.Body.Len() != 0 || rec.Header().Get("Content-Length") != "4" {
+		t.Errorf("Unexpected HEAD response %d %q %+v", rec.Code, rec.Body.String(), rec.Header())
+	}
+}
+
+func TestTileJSON(t *testing.T) {
+	h, err := server.NewHandler(newStore(t), server.Options{Ext: "png", Name: "test"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/tiles/", http.StripPrefix("/tiles", h))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/tiles" + server.TileJSONPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	var tj server.TileJSON
+	if err := json.NewDecoder(resp.Body).Decode(&tj); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tj.TileJSON != "3.0.0" || tj.Name != "test" || tj.MinZoom != 2 || tj.MaxZoom != 3 || len(tj.Tiles) != 1 {
+		t.Errorf("Unexpected TileJSON %+v", tj)
+	}
+	if tj.Tiles[0] != srv.URL+"/tiles/{z}/{x}/{y}.png" {
+		t.Errorf("Tiles template is different: %s", tj.Tiles[0])
+	}
+	if len(tj.Bounds) != 4 || math.Abs(tj.Bounds[0]+90) > 1e-9 || math.Abs(tj.Bounds[2]-90) > 1e-9 || tj.Bounds[1] > 1e-9 || tj.Bounds[3] < 66 {
+		t.Errorf("Bounds are not the extent of the store tiles: %v", tj.Bounds)
+	}
+	tile, err := http.Get(srv.URL + "/tiles/3/4/3.png")
+	if err != nil || tile.StatusCode != 200 {
+		t.Errorf("Tile is not served under the prefix: %v %v", tile, err)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	opts := server.Options{Ext: "png", Bounds: tiling.ExtentG{MinLon: -80, MinLat: 10, MaxLon: -10, MaxLat: 50}}
+	h, err := server.NewHandler(newStore(t), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tj := h.TileJSON(""); tj.MinZoom != 2 || tj.MaxZoom != 3 {
+		t.Errorf("Zoom range is not read from the store: %d-%d", tj.MinZoom, tj.MaxZoom)
+	}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	paths := []string{"/2/1/1.png", "/2/2/1.png", "/3/4/3.png"}
+	codes := []int{200, 404, 404}
+	for i, p := range paths {
+		resp, err := http.Get(srv.URL + p)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != codes[i] {
+			t.Errorf("Status of %s is different (expected, actual) %d != %d", p, codes[i], resp.StatusCode)
+		}
+	}
+}
+
+func TestNewHandlerErrors(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "server")
+	defer os.RemoveAll(dir)
+	if _, err := server.NewHandler(store.NewFS(dir, store.FSOptions{}), server.Options{}); err == nil {
+		t.Errorf("An empty store without bounds should fail")
+	}
+	opts := server.Options{Bounds: tiling.ExtentG{MinLon: -1, MaxLon: 1, MinLat: -1, MaxLat: 1}, MinZoom: 5, MaxZoom: 2}
+	if _, err := server.NewHandler(store.NewFS(dir, store.FSOptions{}), opts); err == nil {
+		t.Errorf("An invalid zoom range should fail")
+	}
+}
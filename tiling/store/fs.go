@@ -0,0 +1,171 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//FSOptions describe the layout of a filesystem store
+type FSOptions struct {
+	//Scheme is the row numbering of the {z}/{x}/{y} paths, XYZ or TMS
+	Scheme tiling.Scheme
+	//Ext is the extension of the tile files without the dot, no extension when empty
+	Ext string
+	//FileMode and DirMode are the permissions of the created files and directories, 0644 and 0755 when zero
+	FileMode os.FileMode
+	DirMode  os.FileMode
+}
+
+//FS stores tiles in a {z}/{x}/{y}.{ext} directory tree
+type FS struct {
+	root string
+	opts FSOptions
+}
+
+//NewFS gives a store rooted at the directory root, which is created when needed
+func NewFS(root string, opts FSOptions) *FS {
+	if opts.FileMode == 0 {
+		opts.FileMode = 0644
+	}
+	if opts.DirMode == 0 {
+		opts.DirMode = 0755
+	}
+	return &FS{root: root, opts: opts}
+}
+
+//Path gives the path of the file of the tile
+func (s *FS) Path(t tiling.Tile) string {
+	t = tiling.ConvertTile(t, tiling.XYZ, s.opts.Scheme)
+	name := strconv.Itoa(t.Y)
+	if s.opts.Ext != "" {
+		name += "." + s.opts.Ext
+	}
+	return filepath.Join(s.root, strconv.Itoa(t.Z), strconv.Itoa(t.X), name)
+}
+
+//Get gives the data of the tile or ErrTileNotFound
+func (s *FS) Get(t tiling.Tile) ([]byte, error) {
+	data, err := ioutil.ReadFile(s.Path(t))
+	if os.IsNotExist(err) {
+		return nil, ErrTileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read tile (x, y, z)(%d, %d, %d): %v", t.X, t.Y, t.Z, err)
+	}
+	return data, nil
+}
+
+//Put stores the data of the tile atomically: it is written to a temporary file renamed in place
+func (s *FS) Put(t tiling.Tile, data []byte) error {
+	path := s.Path(t)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, s.opts.DirMode); err != nil {
+		return fmt.Errorf("Cannot create directory %s: %v", dir, err)
+	}
+	tmp, err := ioutil.TempFile(dir, ".tile-*")
+	if err != nil {
+		return fmt.Errorf("Cannot create temporary file in %s: %v", dir, err)
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), s.opts.FileMode)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("Cannot write tile (x, y, z)(%d, %d, %d): %v", t.X, t.Y, t.Z, err)
+	}
+	return nil
+}
+
+//Delete removes the file of the tile
+func (s *FS) Delete(t tiling.Tile) error {
+	err := os.Remove(s.Path(t))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Cannot delete tile (x, y, z)(%d, %d, %d): %v", t.X, t.Y, t.Z, err)
+	}
+	return nil
+}
+
+//Exists tells if the file of the tile exists
+func (s *FS) Exists(t tiling.Tile) (bool, error) {
+	_, err := os.Stat(s.Path(t))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("Cannot stat tile (x, y, z)(%d, %d, %d): %v", t.X, t.Y, t.Z, err)
+	}
+	return true, nil
+}
+
+//Iterate calls fn for every tile file of the tree, files not following the layout are ignored
+func (s *FS) Iterate(fn func(t tiling.Tile) error) error {
+	zs, err := numericEntries(s.root, true, "")
+	if err != nil {
+		return err
+	}
+	for _, z := range zs {
+		zdir := filepath.Join(s.root, strconv.Itoa(z))
+		xs, err := numericEntries(zdir, true, "")
+		if err != nil {
+			return err
+		}
+		for _, x := range xs {
+			ys, err := numericEntries(filepath.Join(zdir, strconv.Itoa(x)), false, s.opts.Ext)
+			if err != nil {
+				return err
+			}
+			for _, y := range ys {
+				t := tiling.ConvertTile(tiling.Tile{X: x, Y: y, Z: z}, s.opts.Scheme, tiling.XYZ)
+				if err := fn(t); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//numericEntries lists, sorted, the numbers naming the directories or the files with the given extension in dir
+func numericEntries(dir string, dirs bool, ext string) ([]int, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Cannot list %s: %v", dir, err)
+	}
+	var res []int
+	for _, info := range infos {
+		if info.IsDir() != dirs {
+			continue
+		}
+		name := info.Name()
+		if !dirs && ext != "" {
+			if !strings.HasSuffix(name, "."+ext) {
+				continue
+			}
+			name = strings.TrimSuffix(name, "."+ext)
+		}
+		n, err := strconv.Atoi(name)
+		if err != nil || n < 0 {
+			continue
+		}
+		res = append(res, n)
+	}
+	sort.Ints(res)
+	return res, nil
+}
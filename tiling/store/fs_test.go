@@ -0,0 +1,170 @@
+package store_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+	"github.com/trealtamira/gopkgs/tiling/store"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatalf("Cannot create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestFSPath(t *testing.T) {
+	tl := tiling.Tile{X: 33, Y: 23, Z: 6}
+	opts := []store.FSOptions{
+		store.FSOptions{Ext: "png"},
+		store.FSOptions{Scheme: tiling.TMS, Ext: "jpg"},
+		store.FSOptions{},
+	}
+	paths := []string{"6/33/23.png", "6/33/40.jpg", "6/33/23"}
+	for i, o := range opts {
+		s := store.NewFS("root", o)
+		if p := s.Path(tl); p != filepath.Join("root", filepath.FromSlash(paths[i])) {
+			t.Errorf("Path is different (expected, actual) %s != %s", paths[i], p)
+		}
+	}
+}
+
+func TestFS(t *testing.T) {
+	for _, scheme := range []tiling.Scheme{tiling.XYZ, tiling.TMS} {
+		t.Run(fmt.Sprintf("Scheme %v", scheme), func(t *testing.T) {
+			root := tempDir(t)
+			var s store.Store = store.NewFS(root, store.FSOptions{Scheme: scheme, Ext: "png"})
+			tiles := []tiling.Tile{
+				tiling.Tile{X: 0, Y: 0, Z: 0},
+				tiling.Tile{X: 33, Y: 23, Z: 6},
+				tiling.Tile{X: 34, Y: 23, Z: 6},
+				tiling.Tile{X: 5, Y: 8, Z: 10},
+			}
+			for i, tl := range tiles {
+				if err := s.Put(tl, []byte{byte(i)}); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+			ioutil.WriteFile(filepath.Join(root, "6", "33", "notes.txt"), []byte("x"), 0644)
+			os.MkdirAll(filepath.Join(root, "cache"), 0755)
+			for i, tl := range tiles {
+				data, err := s.Get(tl)
+				if err != nil || !bytes.Equal(data, []byte{byte(i)}) {
+					t.Errorf("Tile %+v is different: %v %v", tl, data, err)
+				}
+				if ok, err := s.Exists(tl); !ok || err != nil {
+					t.Errorf("Tile %+v should exist: %v", tl, err)
+				}
+			}
+			var seen []tiling.Tile
+			if err := s.Iterate(func(tl tiling.Tile) error {
+				seen = append(seen, tl)
+				return nil
+			}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(seen) != len(tiles) {
+				t.Fatalf("Iterated %d tiles instead of %d: %+v", len(seen), len(tiles), seen)
+			}
+			for i := range tiles {
+				if seen[i] != tiles[i] {
+					t.Errorf("Iterated tile is different (expected, actual) %+v != %+v", tiles[i], seen[i])
+				}
+			}
+			if err := s.Delete(tiles[1]); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := s.Delete(tiles[1]); err != nil {
+				t.Errorf("Deleting a missing tile should not fail: %v", err)
+			}
+			if _, err := s.Get(tiles[1]); err != store.ErrTileNotFound {
+				t.Errorf("Expected ErrTileNotFound, got %v", err)
+			}
+			if ok, _ := s.Exists(tiles[1]); ok {
+				t.Errorf("Deleted tile should not exist")
+			}
+		})
+	}
+}
+
+func TestFSAtomicPut(t *testing.T) {
+	root := tempDir(t)
+	s := store.NewFS(root, store.FSOptions{Ext: "pbf"})
+	tl := tiling.Tile{X: 1, Y: 1, Z: 1}
+	for _, d := range []string{"first", "second"} {
+		if err := s.Put(tl, []byte(d)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	infos, err := ioutil.ReadDir(filepath.Dir(s.Path(tl)))
+	if err != nil || len(infos) != 1 {
+		t.Errorf("Temporary files were left behind: %v %v", infos, err)
+	}
+	if data, _ := s.Get(tl); string(data) != "second" {
+		t.Errorf("Tile was not replaced: %q", data)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	s := store.NewFS(tempDir(t), store.FSOptions{Ext: "png"})
+	r := tiling.Range{MinX: 2, MaxX: 4, MinY: 2, MaxY: 3, ZL: 4}
+	for _, tl := range []tiling.Tile{{X: 2, Y: 2, Z: 4}, {X: 4, Y: 3, Z: 4}, {X: 5, Y: 3, Z: 4}, {X: 3, Y: 2, Z: 5}} {
+		s.Put(tl, []byte("x"))
+	}
+	var seen []tiling.Tile
+	err := store.Walk(s, r, func(tl tiling.Tile, data []byte) error {
+		seen = append(seen, tl)
+		return nil
+	})
+	if err != nil || len(seen) != 2 || seen[0] != (tiling.Tile{X: 2, Y: 2, Z: 4}) || seen[1] != (tiling.Tile{X: 4, Y: 3, Z: 4}) {
+		t.Errorf("Walked tiles are different: %+v %v", seen, err)
+	}
+	stop := errors.New("stop")
+	if err := store.Walk(s, r, func(tl tiling.Tile, data []byte) error { return stop }); err != stop {
+		t.Errorf("Walk should return the callback error, got %v", err)
+	}
+}
+
+//described is a store recording its extent and zoom range, counting the tiles iterated
+type described struct {
+	store.Store
+	ok       bool
+	iterated int
+}
+
+func (d *described) Describe() (tiling.ExtentG, int, int, bool) {
+	return tiling.ExtentG{MinLon: -10, MinLat: -5, MaxLon: 10, MaxLat: 5}, 3, 9, d.ok
+}
+
+func (d *described) Iterate(fn func(t tiling.Tile) error) error {
+	return d.Store.Iterate(func(t tiling.Tile) error {
+		d.iterated++
+		return fn(t)
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	fs := store.NewFS(tempDir(t), store.FSOptions{})
+	fs.Put(tiling.Tile{X: 1, Y: 1, Z: 2}, []byte("a"))
+	fs.Put(tiling.Tile{X: 4, Y: 3, Z: 3}, []byte("b"))
+	d := &described{Store: fs, ok: true}
+	ext, minZoom, maxZoom, err := store.Describe(d)
+	if err != nil || ext.MinLon != -10 || minZoom != 3 || maxZoom != 9 || d.iterated != 0 {
+		t.Errorf("Recorded description not used: %+v %d-%d %v, %d tiles iterated", ext, minZoom, maxZoom, err, d.iterated)
+	}
+	d.ok = false
+	ext, minZoom, maxZoom, err = store.Describe(d)
+	if err != nil || minZoom != 2 || maxZoom != 3 || math.Abs(ext.MinLon+90) > 1e-9 || d.iterated != 2 {
+		t.Errorf("Missing description should scan the tiles: %+v %d-%d %v, %d tiles iterated", ext, minZoom, maxZoom, err, d.iterated)
+	}
+}
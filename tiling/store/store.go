@@ -0,0 +1,88 @@
+//Package store defines a storage of tiles addressed by tiling.Tile and implements it on the filesystem
+package store
+
+import (
+	"errors"
+	"math"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+//ErrTileNotFound is returned when reading a tile missing from the store
+var ErrTileNotFound = errors.New("Tile not found")
+
+//Store is a storage of tiles, tiles are always addressed in the XYZ scheme
+type Store interface {
+	//Get gives the data of the tile or ErrTileNotFound
+	Get(t tiling.Tile) ([]byte, error)
+	//Put stores the data of the tile, replacing the previous one
+	Put(t tiling.Tile, data []byte) error
+	//Delete removes the tile, deleting a missing tile is not an error
+	Delete(t tiling.Tile) error
+	//Exists tells if the tile is in the store
+	Exists(t tiling.Tile) (bool, error)
+	//Iterate calls fn for every tile of the store until fn returns an error, which is returned
+	Iterate(fn func(t tiling.Tile) error) error
+}
+
+//Walk calls fn for every tile of the range found in the store, row by row, until fn returns an error
+func Walk(s Store, r tiling.Range, fn func(t tiling.Tile, data []byte) error) error {
+	var err error
+	r.Each(func(t tiling.Tile) bool {
+		var data []byte
+		data, err = s.Get(t)
+		if err == ErrTileNotFound {
+			err = nil
+			return true
+		}
+		if err == nil {
+			err = fn(t, data)
+		}
+		return err == nil
+	})
+	return err
+}
+
+//Describer is implemented by stores knowing the geo extent and the zoom range of their tiles without iterating
+//over them, as from the MBTiles metadata or the PMTiles header. ok is false when they are not recorded.
+type Describer interface {
+	Describe() (ext tiling.ExtentG, minZoom, maxZoom int, ok bool)
+}
+
+//Describe gives the geo extent and the zoom range of the tiles of the store, from the store itself when it is
+//a Describer recording them, otherwise iterating over all the tiles.
+//It returns ErrTileNotFound when the store is empty.
+func Describe(s Store) (tiling.ExtentG, int, int, error) {
+	if d, ok := s.(Describer); ok {
+		if ext, minZoom, maxZoom, ok := d.Describe(); ok {
+			return ext, minZoom, maxZoom, nil
+		}
+	}
+	var ext tiling.ExtentG
+	minZoom, maxZoom := -1, -1
+	err := s.Iterate(func(t tiling.Tile) error {
+		te := tiling.MercToGeoExt(tiling.ExtentOf(t))
+		if minZoom < 0 {
+			ext, minZoom, maxZoom = te, t.Z, t.Z
+			return nil
+		}
+		ext.MinLat = math.Min(ext.MinLat, te.MinLat)
+		ext.MinLon = math.Min(ext.MinLon, te.MinLon)
+		ext.MaxLat = math.Max(ext.MaxLat, te.MaxLat)
+		ext.MaxLon = math.Max(ext.MaxLon, te.MaxLon)
+		if t.Z < minZoom {
+			minZoom = t.Z
+		}
+		if t.Z > maxZoom {
+			maxZoom = t.Z
+		}
+		return nil
+	})
+	if err != nil {
+		return ext, 0, 0, err
+	}
+	if minZoom < 0 {
+		return ext, 0, 0, ErrTileNotFound
+	}
+	return ext, minZoom, maxZoom, nil
+}
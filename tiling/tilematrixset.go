@@ -0,0 +1,323 @@
+package tiling
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	//WebMercatorQuadID is the identifier of the OGC tile matrix set of EPSG:3857, the one of ZoomLevel
+	WebMercatorQuadID = "WebMercatorQuad"
+	//WorldCRS84QuadID is the identifier of the OGC tile matrix set of geographic coordinates, 2x1 tiles at zoom 0
+	WorldCRS84QuadID = "WorldCRS84Quad"
+	//CRS84 is the URI of the WGS84 geographic reference system with longitude first
+	CRS84 = "http://www.opengis.net/def/crs/OGC/1.3/CRS84"
+	//builtinLevels is the number of tile matrices of the built in tile matrix sets
+	builtinLevels = 25
+	//metersPerDegree is the length of a degree along the equator of the WGS84 sphere
+	metersPerDegree = equator / 360
+)
+
+//TileMatrix is a single level of a TileMatrixSet: a grid of MatrixWidth x MatrixHeight tiles of
+//TileWidth x TileHeight pixels. Coordinates are expressed in the units of the set CRS, with N as the
+//vertical axis and E as the horizontal one: for geographic sets N is the latitude and E the longitude.
+type TileMatrix struct {
+	ID               string
+	Level            int
+	ScaleDenominator float64
+	CellSize         float64
+	Origin           PointM
+	BottomLeft       bool
+	TileWidth        int
+	TileHeight       int
+	MatrixWidth      int
+	MatrixHeight     int
+}
+
+//TileMatrixSet is a pyramid of tile matrices on a reference system, as defined by the OGC
+//Two Dimensional Tile Matrix Set standard http://docs.opengeospatial.org/is/17-083r4/17-083r4.html
+type TileMatrixSet struct {
+	ID       string
+	CRS      string
+	Bounds   ExtentM
+	Matrices []TileMatrix
+}
+
+//tileSpan gives the size in CRS units of a tile of the matrix
+func (m *TileMatrix) tileSpan() (float64, float64) {
+	return m.CellSize * float64(m.TileWidth), m.CellSize * float64(m.TileHeight)
+}
+
+//TileOf gives the tile of the matrix containing the given point, in CRS units
+func (m *TileMatrix) TileOf(p PointM) Tile {
+	w, h := m.tileSpan()
+	col := math.Floor((p.E - m.Origin.E) / w)
+	row := math.Floor((m.Origin.N - p.N) / h)
+	if m.BottomLeft {
+		row = math.Floor((p.N - m.Origin.N) / h)
+	}
+	return Tile{X: int(col), Y: int(row), Z: m.Level}
+}
+
+//ExtentOfTile gives the extent, in CRS units, of the tile of the matrix at column x and row y
+func (m *TileMatrix) ExtentOfTile(x, y int) ExtentM {
+	w, h := m.tileSpan()
+	ext := ExtentM{West: m.Origin.E + float64(x)*w, East: m.Origin.E + float64(x+1)*w}
+	if m.BottomLeft {
+		ext.South = m.Origin.N + float64(y)*h
+		ext.North = m.Origin.N + float64(y+1)*h
+	} else {
+		ext.North = m.Origin.N - float64(y)*h
+		ext.South = m.Origin.N - float64(y+1)*h
+	}
+	return ext
+}
+
+//RangeOf gives the range of tiles of the matrix covering the given extent, in CRS units,
+//and false if the extent is outside the matrix
+func (m *TileMatrix) RangeOf(ext ExtentM) (Range, bool) {
+	a := m.TileOf(ext.UL())
+	b := m.TileOf(ext.LR())
+	r := Range{MinX: a.X, MaxX: b.X, MinY: minInt(a.Y, b.Y), MaxY: maxInt(a.Y, b.Y), ZL: m.Level}
+	bounds := Range{MinX: 0, MaxX: m.MatrixWidth - 1, MinY: 0, MaxY: m.MatrixHeight - 1, ZL: m.Level}
+	return RangeIntersection(r, bounds)
+}
+
+//Matrix gives the tile matrix at level z
+func (s *TileMatrixSet) Matrix(z int) (*TileMatrix, error) {
+	if z < 0 || z >= len(s.Matrices) {
+		return nil, fmt.Errorf("Tile matrix set %s has no level %d", s.ID, z)
+	}
+	return &s.Matrices[z], nil
+}
+
+//MatrixByID gives the tile matrix with the given identifier
+func (s *TileMatrixSet) MatrixByID(id string) (*TileMatrix, error) {
+	for i := range s.Matrices {
+		if s.Matrices[i].ID == id {
+			return &s.Matrices[i], nil
+		}
+	}
+	return nil, fmt.Errorf("Tile matrix set %s has no tile matrix %q", s.ID, id)
+}
+
+//TileMatrix gives the description of the zoom level as a level of the WebMercatorQuad tile matrix set
+func (z *ZoomLevel) TileMatrix() TileMatrix {
+	origin := PointM{N: meridian, E: -equator / 2}
+	if !z.scheme.topLeft() {
+		origin.N = -meridian
+	}
+	return TileMatrix{
+		ID:               fmt.Sprintf("%d", z.zoom),
+		Level:            z.zoom,
+		ScaleDenominator: z.ScaleDenominator(),
+		CellSize:         z.Resolution(),
+		Origin:           origin,
+		BottomLeft:       !z.scheme.topLeft(),
+		TileWidth:        z.size,
+		TileHeight:       z.size,
+		MatrixWidth:      int(z.mxSize),
+		MatrixHeight:     int(z.mxSize),
+	}
+}
+
+//WebMercatorQuad builds the OGC tile matrix set of EPSG:3857 used by ZoomLevel, with 256 pixels tiles
+func WebMercatorQuad() *TileMatrixSet {
+	s := &TileMatrixSet{
+		ID:       WebMercatorQuadID,
+		CRS:      "http://www.opengis.net/def/crs/EPSG/0/" + EPSG,
+		Bounds:   ExtentM{West: -equator / 2, East: equator / 2, South: -meridian, North: meridian},
+		Matrices: make([]TileMatrix, builtinLevels),
+	}
+	for z := range s.Matrices {
+		s.Matrices[z] = NewZoomLevel(z).TileMatrix()
+	}
+	return s
+}
+
+//WorldCRS84Quad builds the OGC tile matrix set of geographic coordinates with 256 pixels tiles,
+//it has 2x1 tiles at zoom 0 and uses longitudes as E and latitudes as N
+func WorldCRS84Quad() *TileMatrixSet {
+	s := &TileMatrixSet{
+		ID:       WorldCRS84QuadID,
+		CRS:      CRS84,
+		Bounds:   ExtentM{West: -180, East: 180, South: -90, North: 90},
+		Matrices: make([]TileMatrix, builtinLevels),
+	}
+	for z := range s.Matrices {
+		cell := 180 / float64(DefaultTileSize) / math.Pow(2, float64(z))
+		s.Matrices[z] = TileMatrix{
+			ID:               fmt.Sprintf("%d", z),
+			Level:            z,
+			ScaleDenominator: cell * metersPerDegree / standardPixelSize,
+			CellSize:         cell,
+			Origin:           PointM{N: 90, E: -180},
+			TileWidth:        DefaultTileSize,
+			TileHeight:       DefaultTileSize,
+			MatrixWidth:      2 << uint(z),
+			MatrixHeight:     1 << uint(z),
+		}
+	}
+	return s
+}
+
+//tmsJSON is the JSON encoding of a tile matrix set, both of version 2.0 and of version 1.0 of the standard
+type tmsJSON struct {
+	ID          string          `json:"id"`
+	Identifier  string          `json:"identifier"`
+	CRS         json.RawMessage `json:"crs"`
+	Supported   string          `json:"supportedCRS"`
+	OrderedAxes []string        `json:"orderedAxes"`
+	BoundingBox *struct {
+		LowerLeft   []float64 `json:"lowerLeft"`
+		UpperRight  []float64 `json:"upperRight"`
+		LowerCorner []float64 `json:"lowerCorner"`
+		UpperCorner []float64 `json:"upperCorner"`
+	} `json:"boundingBox"`
+	TileMatrices []tmJSON `json:"tileMatrices"`
+	TileMatrix   []tmJSON `json:"tileMatrix"`
+}
+
+type tmJSON struct {
+	ID               string    `json:"id"`
+	Identifier       string    `json:"identifier"`
+	ScaleDenominator float64   `json:"scaleDenominator"`
+	CellSize         float64   `json:"cellSize"`
+	CornerOfOrigin   string    `json:"cornerOfOrigin"`
+	PointOfOrigin    []float64 `json:"pointOfOrigin"`
+	TopLeftCorner    []float64 `json:"topLeftCorner"`
+	TileWidth        int       `json:"tileWidth"`
+	TileHeight       int       `json:"tileHeight"`
+	MatrixWidth      int       `json:"matrixWidth"`
+	MatrixHeight     int       `json:"matrixHeight"`
+}
+
+//ParseTileMatrixSet reads an OGC TileMatrixSet JSON definition, version 2.0 or 1.0.
+//Points are read in the axis order given by orderedAxes or, without it as in version 1.0, in the axis order of the CRS:
+//latitude first for EPSG:4326, easting first otherwise.
+func ParseTileMatrixSet(data []byte) (*TileMatrixSet, error) {
+	var j tmsJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("Invalid tile matrix set: %v", err)
+	}
+	s := &TileMatrixSet{ID: firstNonEmpty(j.ID, j.Identifier), CRS: j.Supported}
+	if len(j.CRS) > 0 {
+		crs, err := parseCRS(j.CRS)
+		if err != nil {
+			return nil, err
+		}
+		s.CRS = crs
+	}
+	northFirst := isLatitudeFirstCRS(s.CRS)
+	if len(j.OrderedAxes) > 0 {
+		northFirst = isNorthAxis(j.OrderedAxes[0])
+	}
+	point := func(c []float64, what string) (PointM, error) {
+		if len(c) < 2 {
+			return PointM{}, fmt.Errorf("Tile matrix set %s: %s needs 2 coordinates, got %d", s.ID, what, len(c))
+		}
+		if northFirst {
+			return PointM{N: c[0], E: c[1]}, nil
+		}
+		return PointM{E: c[0], N: c[1]}, nil
+	}
+	if j.BoundingBox != nil {
+		lower, upper := j.BoundingBox.LowerLeft, j.BoundingBox.UpperRight
+		if lower == nil && upper == nil {
+			lower, upper = j.BoundingBox.LowerCorner, j.BoundingBox.UpperCorner
+		}
+		ll, err := point(lower, "lower corner of the bounding box")
+		if err != nil {
+			return nil, err
+		}
+		ur, err := point(upper, "upper corner of the bounding box")
+		if err != nil {
+			return nil, err
+		}
+		s.Bounds = ExtentM{West: ll.E, South: ll.N, East: ur.E, North: ur.N}
+	}
+	matrices := j.TileMatrices
+	if len(matrices) == 0 {
+		matrices = j.TileMatrix
+	}
+	if len(matrices) == 0 {
+		return nil, fmt.Errorf("Tile matrix set %s has no tile matrices", s.ID)
+	}
+	unit := 1.0
+	if isGeographicCRS(s.CRS) {
+		unit = metersPerDegree
+	}
+	for i, m := range matrices {
+		tm := TileMatrix{
+			ID:               firstNonEmpty(m.ID, m.Identifier),
+			Level:            i,
+			ScaleDenominator: m.ScaleDenominator,
+			CellSize:         m.CellSize,
+			BottomLeft:       strings.EqualFold(m.CornerOfOrigin, "bottomLeft"),
+			TileWidth:        m.TileWidth,
+			TileHeight:       m.TileHeight,
+			MatrixWidth:      m.MatrixWidth,
+			MatrixHeight:     m.MatrixHeight,
+		}
+		if tm.CellSize == 0 {
+			tm.CellSize = tm.ScaleDenominator * standardPixelSize / unit
+		}
+		origin := m.PointOfOrigin
+		if origin == nil {
+			origin = m.TopLeftCorner
+		}
+		o, err := point(origin, fmt.Sprintf("origin of tile matrix %q", tm.ID))
+		if err != nil {
+			return nil, err
+		}
+		tm.Origin = o
+		if tm.CellSize <= 0 || tm.TileWidth <= 0 || tm.TileHeight <= 0 || tm.MatrixWidth <= 0 || tm.MatrixHeight <= 0 {
+			return nil, fmt.Errorf("Tile matrix set %s: tile matrix %q has invalid sizes", s.ID, tm.ID)
+		}
+		s.Matrices = append(s.Matrices, tm)
+	}
+	return s, nil
+}
+
+//parseCRS reads a CRS given either as a URI string or as an object with an uri member
+func parseCRS(raw json.RawMessage) (string, error) {
+	var uri string
+	if err := json.Unmarshal(raw, &uri); err == nil {
+		return uri, nil
+	}
+	var obj struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return "", fmt.Errorf("Invalid tile matrix set crs: %v", err)
+	}
+	return obj.URI, nil
+}
+
+func isNorthAxis(name string) bool {
+	switch strings.ToLower(name) {
+	case "lat", "latitude", "y", "n", "north", "northing":
+		return true
+	}
+	return false
+}
+
+func isGeographicCRS(crs string) bool {
+	return strings.HasSuffix(crs, "CRS84") || strings.HasSuffix(crs, "/4326") || strings.HasSuffix(crs, ":4326")
+}
+
+//isLatitudeFirstCRS tells if the CRS is EPSG:4326, whose axis order is latitude first unlike CRS84
+func isLatitudeFirstCRS(crs string) bool {
+	return isGeographicCRS(crs) && !strings.HasSuffix(crs, "CRS84")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package tiling_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestWebMercatorQuad(t *testing.T) {
+	tms := tiling.WebMercatorQuad()
+	mercs := []tiling.PointM{
+		tiling.PointM{E: 910763.1357121654, N: 5309377.085697312},
+		tiling.PointM{E: -6514065.628545966, N: -259688.542848654},
+		tiling.PointM{E: 12665509.838740565, N: -3025789.0757535584},
+	}
+	zooms := []int{6, 4, 17}
+	for i, p := range mercs {
+		t.Run(fmt.Sprintf("Point %d (E,N)(%f, %f)", i, p.E, p.N), func(t *testing.T) {
+			m, err := tms.Matrix(zooms[i])
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			zl := tiling.NewZoomLevel(zooms[i])
+			tl := m.TileOf(p)
+			if tl != zl.TileOfMerc(p) {
+				t.Errorf("Tile is different (expected, actual) %+v != %+v", zl.TileOfMerc(p), tl)
+			}
+			if !tiling.Equals(m.ExtentOfTile(tl.X, tl.Y), zl.ExtentOfTile(tl.X, tl.Y)) {
+				t.Errorf("Extent of tile is different from ZoomLevel")
+			}
+			tmsZl := zl.WithScheme(tiling.TMS)
+			bl := tmsZl.TileMatrix()
+			if bl.TileOf(p) != tmsZl.TileOfMerc(p) {
+				t.Errorf("Bottom left tile is different (expected, actual) %+v != %+v", tmsZl.TileOfMerc(p), bl.TileOf(p))
+			}
+		})
+	}
+	if _, err := tms.Matrix(25); err == nil {
+		t.Errorf("Level 25 should not exist")
+	}
+	if m, err := tms.MatrixByID("0"); err != nil || math.Abs(m.ScaleDenominator-559082264.0287178) > 0.0001 {
+		t.Errorf("Wrong level 0: %+v %v", m, err)
+	}
+}
+
+func TestWorldCRS84Quad(t *testing.T) {
+	tms := tiling.WorldCRS84Quad()
+	m0, _ := tms.Matrix(0)
+	if m0.MatrixWidth != 2 || m0.MatrixHeight != 1 {
+		t.Errorf("Zoom 0 should have 2x1 tiles, got %dx%d", m0.MatrixWidth, m0.MatrixHeight)
+	}
+	if math.Abs(m0.ScaleDenominator-279541132.0143589) > 0.0001 {
+		t.Errorf("Scale denominator is different (expected, actual) %f != %f", 279541132.0143589, m0.ScaleDenominator)
+	}
+	points := []tiling.PointM{
+		tiling.PointM{E: 10, N: 45},
+		tiling.PointM{E: -100, N: -30},
+		tiling.PointM{E: 179.9, N: -89.9},
+	}
+	zooms := []int{1, 0, 3}
+	expected := []tiling.Tile{
+		tiling.Tile{X: 2, Y: 0, Z: 1},
+		tiling.Tile{X: 0, Y: 0, Z: 0},
+		tiling.Tile{X: 15, Y: 7, Z: 3},
+	}
+	for i, p := range points {
+		t.Run(fmt.Sprintf("Point %d (Lon,Lat)(%f, %f)", i, p.E, p.N), func(t *testing.T) {
+			m, _ := tms.Matrix(zooms[i])
+			tl := m.TileOf(p)
+			if tl != expected[i] {
+				t.Errorf("Tile is different (expected, actual) %+v != %+v", expected[i], tl)
+			}
+			ext := m.ExtentOfTile(tl.X, tl.Y)
+			if p.E < ext.West || p.E > ext.East || p.N < ext.South || p.N > ext.North {
+				t.Errorf("Point is outside the extent of its tile %+v", ext)
+			}
+		})
+	}
+	m2, _ := tms.Matrix(2)
+	r, ok := m2.RangeOf(tiling.ExtentM{West: -200, East: -10, South: 10, North: 100})
+	expectedRange := tiling.Range{MinX: 0, MaxX: 3, MinY: 0, MaxY: 1, ZL: 2}
+	if !ok || r != expectedRange {
+		t.Errorf("Range is different (expected, actual) %+v != %+v", expectedRange, r)
+	}
+}
+
+func TestParseTileMatrixSet(t *testing.T) {
+	v2 := `{
+		"id": "WorldCRS84Quad",
+		"crs": {"uri": "http://www.opengis.net/def/crs/EPSG/0/4326"},
+		"orderedAxes": ["Lat", "Lon"],
+		"boundingBox": {"lowerLeft": [-90, -180], "upperRight": [90, 180]},
+		"tileMatrices": [
+			{"id": "0", "scaleDenominator": 279541132.0143589, "pointOfOrigin": [90, -180],
+			 "tileWidth": 256, "tileHeight": 256, "matrixWidth": 2, "matrixHeight": 1},
+			{"id": "1", "scaleDenominator": 139770566.00717944, "cellSize": 0.3515625, "cornerOfOrigin": "topLeft",
+			 "pointOfOrigin": [90, -180], "tileWidth": 256, "tileHeight": 256, "matrixWidth": 4, "matrixHeight": 2}
+		]
+	}`
+	s, err := tiling.ParseTileMatrixSet([]byte(v2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	builtin := tiling.WorldCRS84Quad()
+	if s.ID != "WorldCRS84Quad" || len(s.Matrices) != 2 || s.Bounds != builtin.Bounds {
+		t.Errorf("Wrong set %+v", s)
+	}
+	for z := range s.Matrices {
+		b, _ := builtin.Matrix(z)
+		m := s.Matrices[z]
+		if math.Abs(m.CellSize-b.CellSize) > 0.0000001 || m.Origin != b.Origin || m.MatrixWidth != b.MatrixWidth {
+			t.Errorf("Matrix %d is different (expected, actual) %+v != %+v", z, *b, m)
+		}
+	}
+
+	v1 := `{
+		"type": "TileMatrixSetType",
+		"identifier": "Custom",
+		"supportedCRS": "http://www.opengis.net/def/crs/EPSG/0/3857",
+		"boundingBox": {"type": "BoundingBoxType", "crs": "http://www.opengis.net/def/crs/EPSG/0/3857",
+			"lowerCorner": [-20037508.3427892, -20037508.3427892], "upperCorner": [20037508.3427892, 20037508.3427892]},
+		"tileMatrix": [
+			{"identifier": "a", "scaleDenominator": 559082264.0287178, "topLeftCorner": [-20037508.3427892, 20037508.3427892],
+			 "tileWidth": 256, "tileHeight": 256, "matrixWidth": 1, "matrixHeight": 1}
+		]
+	}`
+	s, err = tiling.ParseTileMatrixSet([]byte(v1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m, err := s.MatrixByID("a")
+	if err != nil || math.Abs(m.CellSize-156543.03392804097) > 0.0000001 {
+		t.Errorf("Wrong matrix %+v %v", m, err)
+	}
+	if s.Bounds != (tiling.ExtentM{West: -20037508.3427892, South: -20037508.3427892, East: 20037508.3427892, North: 20037508.3427892}) {
+		t.Errorf("Bounds of the v1 set are different: %+v", s.Bounds)
+	}
+
+	v1Geo := `{
+		"type": "TileMatrixSetType",
+		"identifier": "Geo",
+		"supportedCRS": "urn:ogc:def:crs:EPSG::4326",
+		"boundingBox": {"type": "BoundingBoxType", "crs": "urn:ogc:def:crs:EPSG::4326",
+			"lowerCorner": [-90, -180], "upperCorner": [90, 180]},
+		"tileMatrix": [
+			{"identifier": "0", "scaleDenominator": 279541132.0143589, "topLeftCorner": [90, -180],
+			 "tileWidth": 256, "tileHeight": 256, "matrixWidth": 2, "matrixHeight": 1}
+		]
+	}`
+	s, err = tiling.ParseTileMatrixSet([]byte(v1Geo))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if o := s.Matrices[0].Origin; o != builtin.Matrices[0].Origin {
+		t.Errorf("Origin of the v1 EPSG:4326 set is different (expected, actual) %+v != %+v", builtin.Matrices[0].Origin, o)
+	}
+	if s.Bounds != builtin.Bounds {
+		t.Errorf("Bounds of the v1 EPSG:4326 set are different (expected, actual) %+v != %+v", builtin.Bounds, s.Bounds)
+	}
+
+	invalid := []string{
+		`nope`,
+		`{"id": "x", "crs": "EPSG:3857"}`,
+		`{"id": "x", "crs": 3, "tileMatrices": []}`,
+		`{"id": "x", "crs": "EPSG:3857", "tileMatrices": [{"id": "0", "cellSize": 1, "pointOfOrigin": [0],
+			"tileWidth": 256, "tileHeight": 256, "matrixWidth": 1, "matrixHeight": 1}]}`,
+		`{"id": "x", "crs": "EPSG:3857", "tileMatrices": [{"id": "0", "cellSize": 1, "pointOfOrigin": [0, 0],
+			"tileWidth": 0, "tileHeight": 256, "matrixWidth": 1, "matrixHeight": 1}]}`,
+	}
+	for i, d := range invalid {
+		if s, err := tiling.ParseTileMatrixSet([]byte(d)); err == nil {
+			t.Errorf("Document %d should fail, got %+v", i, s)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package tiling
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	utmScaleFactor   = 0.9996
+	utmFalseEasting  = 500000.0
+	utmFalseNorthing = 10000000.0
+)
+
+//Krüger series coefficients for the WGS84 ellipsoid, third order in the third flattening n
+//https://en.wikipedia.org/wiki/Universal_Transverse_Mercator_coordinate_system#Simplified_formulae
+var (
+	utmN      = wgs84Flattening / (2 - wgs84Flattening)
+	utmRadius = wgs84SphericalAxis / (1 + utmN) * (1 + utmN*utmN/4 + math.Pow(utmN, 4)/64)
+	utmAlpha  = [3]float64{
+		utmN/2 - 2*utmN*utmN/3 + 5*math.Pow(utmN, 3)/16,
+		13*utmN*utmN/48 - 3*math.Pow(utmN, 3)/5,
+		61 * math.Pow(utmN, 3) / 240,
+	}
+	utmBeta = [3]float64{
+		utmN/2 - 2*utmN*utmN/3 + 37*math.Pow(utmN, 3)/96,
+		utmN*utmN/48 + math.Pow(utmN, 3)/15,
+		17 * math.Pow(utmN, 3) / 480,
+	}
+	utmDelta = [3]float64{
+		2*utmN - 2*utmN*utmN/3 - 2*math.Pow(utmN, 3),
+		7*utmN*utmN/3 - 8*math.Pow(utmN, 3)/5,
+		56 * math.Pow(utmN, 3) / 15,
+	}
+)
+
+//UTM is the Universal Transverse Mercator projection of a zone, on the WGS84 ellipsoid (EPSG:326xx and 327xx)
+type UTM struct {
+	zone  int
+	north bool
+}
+
+//NewUTM gives the UTM projection of the zone, between 1 and 60, in the northern or southern hemisphere
+func NewUTM(zone int, north bool) (UTM, error) {
+	if zone < 1 || zone > 60 {
+		return UTM{}, fmt.Errorf("UTM zone %d out of range [1, 60]", zone)
+	}
+	return UTM{zone: zone, north: north}, nil
+}
+
+//UTMOf gives the UTM projection of the zone containing the given point,
+//taking into account the exceptions of Norway and Svalbard
+func UTMOf(g PointG) (UTM, error) {
+	if g.Lat < -80 || g.Lat > 84 {
+		return UTM{}, fmt.Errorf("Point out of UTM limits: (lat, lon)(%f, %f)", g.Lat, g.Lon)
+	}
+	lon := NormalizeLon(g.Lon)
+	zone := int(math.Floor((lon+180)/6)) + 1
+	switch {
+	case g.Lat >= 56 && g.Lat < 64 && lon >= 3 && lon < 12:
+		zone = 32
+	case g.Lat >= 72 && lon >= 0 && lon < 9:
+		zone = 31
+	case g.Lat >= 72 && lon >= 9 && lon < 21:
+		zone = 33
+	case g.Lat >= 72 && lon >= 21 && lon < 33:
+		zone = 35
+	case g.Lat >= 72 && lon >= 33 && lon < 42:
+		zone = 37
+	}
+	return UTM{zone: zone, north: g.Lat >= 0}, nil
+}
+
+//Zone returns the UTM zone number
+func (u UTM) Zone() int {
+	return u.zone
+}
+
+//North tells if the projection is the one of the northern hemisphere
+func (u UTM) North() bool {
+	return u.north
+}
+
+//centralMeridian gives the central meridian of the zone in radians
+func (u UTM) centralMeridian() float64 {
+	return float64(u.zone*6-183) * deg2rad
+}
+
+func (u UTM) falseNorthing() float64 {
+	if u.north {
+		return 0
+	}
+	return utmFalseNorthing
+}
+
+//Forward projects the given geo point, whose longitude may be given on either side of the antimeridian
+func (u UTM) Forward(g PointG) PointM {
+	phi := g.Lat * deg2rad
+	//the longitude from the central meridian is wrapped, so zones next to the antimeridian accept both its sides
+	lam := NormalizeLon(g.Lon-u.centralMeridian()*rad2deg) * deg2rad
+	c := 2 * math.Sqrt(utmN) / (1 + utmN)
+	t := math.Sinh(math.Atanh(math.Sin(phi)) - c*math.Atanh(c*math.Sin(phi)))
+	xi := math.Atan(t / math.Cos(lam))
+	eta := math.Atanh(math.Sin(lam) / math.Sqrt(1+t*t))
+	e, n := eta, xi
+	for j, a := range utmAlpha {
+		k := 2 * float64(j+1)
+		e += a * math.Cos(k*xi) * math.Sinh(k*eta)
+		n += a * math.Sin(k*xi) * math.Cosh(k*eta)
+	}
+	return PointM{
+		E: utmFalseEasting + utmScaleFactor*utmRadius*e,
+		N: u.falseNorthing() + utmScaleFactor*utmRadius*n,
+	}
+}
+
+//Inverse gives the geo point of the given projected point
+func (u UTM) Inverse(m PointM) PointG {
+	xi := (m.N - u.falseNorthing()) / (utmScaleFactor * utmRadius)
+	eta := (m.E - utmFalseEasting) / (utmScaleFactor * utmRadius)
+	xi1, eta1 := xi, eta
+	for j, b := range utmBeta {
+		k := 2 * float64(j+1)
+		xi1 -= b * math.Sin(k*xi) * math.Cosh(k*eta)
+		eta1 -= b * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+	chi := math.Asin(math.Sin(xi1) / math.Cosh(eta1))
+	phi := chi
+	for j, d := range utmDelta {
+		phi += d * math.Sin(2*float64(j+1)*chi)
+	}
+	lam := u.centralMeridian() + math.Atan2(math.Sinh(eta1), math.Cos(xi1))
+	return PointG{Lat: phi * rad2deg, Lon: NormalizeLon(lam * rad2deg)}
+}
+
+//EPSG returns the code of the UTM zone on WGS84
+func (u UTM) EPSG() string {
+	if u.north {
+		return fmt.Sprintf("326%02d", u.zone)
+	}
+	return fmt.Sprintf("327%02d", u.zone)
+}
@@ -0,0 +1,106 @@
+package tiling_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/trealtamira/gopkgs/tiling"
+)
+
+func TestUTM(t *testing.T) {
+	geos := []tiling.PointG{
+		tiling.PointG{Lon: 9.1682557, Lat: 45.4498397},
+		tiling.PointG{Lon: 115.8741812, Lat: -31.8973283},
+		tiling.PointG{Lon: -123.1177155, Lat: 49.2813121},
+		tiling.PointG{Lon: -68.9386812, Lat: -22.315348},
+		tiling.PointG{Lon: 25.7686782, Lat: 71.1673627},
+	}
+	zones := []int{32, 50, 10, 19, 35}
+	codes := []string{"32632", "32750", "32610", "32719", "32635"}
+	utms := []tiling.PointM{
+		tiling.PointM{E: 513156.9463, N: 5032937.6329},
+		tiling.PointM{E: 393539.0209, N: 6470391.8811},
+		tiling.PointM{E: 491438.5861, N: 5458735.3444},
+		tiling.PointM{E: 506315.2901, N: 7532266.4833},
+		tiling.PointM{E: 455640.2828, N: 7896514.0230},
+	}
+	for i, g := range geos {
+		t.Run(fmt.Sprintf("Point %d (Lat,Lon)(%f, %f)", i, g.Lat, g.Lon), func(t *testing.T) {
+			u, err := tiling.UTMOf(g)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if u.Zone() != zones[i] || u.North() != (g.Lat >= 0) || u.EPSG() != codes[i] {
+				t.Errorf("Zone is different (expected, actual) %d %s != %d %s", zones[i], codes[i], u.Zone(), u.EPSG())
+			}
+			m := u.Forward(g)
+			if math.Abs(m.N-utms[i].N) > 0.01 || math.Abs(m.E-utms[i].E) > 0.01 {
+				t.Errorf("Projected point is different (expected, actual) %+v != %+v", utms[i], m)
+			}
+			b := u.Inverse(m)
+			if math.Abs(b.Lat-g.Lat) > 0.0000001 || math.Abs(b.Lon-g.Lon) > 0.0000001 {
+				t.Errorf("Inverse point is different (expected, actual) %+v != %+v", g, b)
+			}
+		})
+	}
+}
+
+func TestUTMAntimeridian(t *testing.T) {
+	u, err := tiling.UTMOf(tiling.PointG{Lon: -177, Lat: 10})
+	if err != nil || u.Zone() != 1 {
+		t.Fatalf("Unexpected zone %d %v", u.Zone(), err)
+	}
+	west := u.Forward(tiling.PointG{Lon: 179, Lat: 10})
+	east := u.Forward(tiling.PointG{Lon: -173, Lat: 10})
+	if math.Abs(west.E+east.E-1000000) > 0.01 || math.Abs(west.N-east.N) > 0.01 {
+		t.Errorf("Points 4° from the central meridian are not symmetric: %+v %+v", west, east)
+	}
+	if b := u.Inverse(west); math.Abs(b.Lon-179) > 0.0000001 || math.Abs(b.Lat-10) > 0.0000001 {
+		t.Errorf("Inverse point is different (expected, actual) (10, 179) != %+v", b)
+	}
+}
+
+func TestUTMOfExceptions(t *testing.T) {
+	geos := []tiling.PointG{
+		tiling.PointG{Lon: 5.3, Lat: 60.4},
+		tiling.PointG{Lon: 15.6, Lat: 78.2},
+		tiling.PointG{Lon: 179.9, Lat: -10},
+		tiling.PointG{Lon: -180, Lat: 10},
+	}
+	zones := []int{32, 33, 60, 1}
+	for i, g := range geos {
+		u, err := tiling.UTMOf(g)
+		if err != nil || u.Zone() != zones[i] {
+			t.Errorf("Zone of %+v is different (expected, actual) %d != %d %v", g, zones[i], u.Zone(), err)
+		}
+	}
+	if _, err := tiling.UTMOf(tiling.PointG{Lat: 85, Lon: 0}); err == nil {
+		t.Errorf("Polar points should fail")
+	}
+	if _, err := tiling.NewUTM(61, true); err == nil {
+		t.Errorf("Zone 61 should fail")
+	}
+}
+
+func TestTileOfProjected(t *testing.T) {
+	u, _ := tiling.NewUTM(32, true)
+	g := tiling.PointG{Lon: 9.1682557, Lat: 45.4498397}
+	zl := tiling.NewZoomLevel(12)
+	tl, err := zl.TileOfProjected(u, u.Forward(g))
+	expected, _ := zl.TileOfGeo(g)
+	if err != nil || tl != expected {
+		t.Errorf("Tile is different (expected, actual) %+v != %+v %v", expected, tl, err)
+	}
+	ext := tiling.ExtentM{West: 500000, East: 520000, South: 5020000, North: 5040000}
+	r := zl.RangeOfProjected(u, ext)
+	for _, c := range []tiling.PointM{ext.UL(), ext.UR(), ext.LR(), ext.LL()} {
+		ct, _ := zl.TileOfProjected(u, c)
+		if !r.Contains(ct) {
+			t.Errorf("Range %+v does not contain corner tile %+v", r, ct)
+		}
+	}
+	if _, err := zl.TileOfProjected(tiling.WebMercator{}, tiling.PointM{N: 30000000, E: 0}); err == nil {
+		t.Errorf("Point out of tiling limits should fail")
+	}
+}
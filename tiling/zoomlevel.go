@@ -15,6 +15,9 @@ const EPSG_OLD = "3587"
 // EPSG_OLD_TYPO is the deprecated typo code of the Reference System used in web mapping
 const EPSG_OLD_TYPO = "3785"
 
+//DefaultTileSize is the side in pixels of the tiles of a new ZoomLevel
+const DefaultTileSize = 256
+
 const (
 	tileMaxLon = 179.999999
 	tileMinLon = -179.999999
@@ -44,6 +47,8 @@ type ZoomLevel struct {
 	mxSize  float64
 	hLength float64
 	vLength float64
+	scheme  Scheme
+	size    int
 }
 
 //NewZoomLevel create a new zoomlevel instance at level z
@@ -51,10 +56,23 @@ func NewZoomLevel(z int) *ZoomLevel {
 	matrixSize := float64(math.Pow(2, float64(z)))
 	hTileLength := equator / matrixSize
 	vTileLength := (2 * meridian) / matrixSize
-	zl := ZoomLevel{zoom: z, mxSize: matrixSize, hLength: hTileLength, vLength: vTileLength}
+	zl := ZoomLevel{zoom: z, mxSize: matrixSize, hLength: hTileLength, vLength: vTileLength, size: DefaultTileSize}
 	return &zl
 }
 
+//WithScheme gives a copy of the zoom level that addresses tile rows with the given scheme,
+//a new zoom level uses XYZ
+func (z *ZoomLevel) WithScheme(s Scheme) *ZoomLevel {
+	zl := *z
+	zl.scheme = s
+	return &zl
+}
+
+//Scheme returns the tile addressing scheme of the zoom level
+func (z *ZoomLevel) Scheme() Scheme {
+	return z.scheme
+}
+
 //Cardinality gives the number of tiles in the zoom level
 func (z *ZoomLevel) Cardinality() int64 {
 	return int64(math.Pow(z.mxSize, 2))
@@ -65,8 +83,14 @@ func (z *ZoomLevel) Level() int {
 	return z.zoom
 }
 
-//TileOfMerc gives the tile coordinates for the given point for the current zoom level
+//TileOfMerc gives the tile coordinates for the given point for the current zoom level,
+//the row is numbered according to the zoom level scheme
 func (z *ZoomLevel) TileOfMerc(m PointM) Tile {
+	return ConvertTile(z.xyzTileOfMerc(m), XYZ, z.scheme)
+}
+
+//xyzTileOfMerc gives the XYZ tile coordinates for the given point
+func (z *ZoomLevel) xyzTileOfMerc(m PointM) Tile {
 	x := math.Floor((m.E + (equator / 2)) / z.hLength)
 	y := math.Floor((meridian - m.N) / z.vLength)
 	t := Tile{X: int(x), Y: int(y), Z: int(z.zoom)}
@@ -83,8 +107,16 @@ func (z *ZoomLevel) TileOfGeo(g PointG) (Tile, error) {
 	return t, nil
 }
 
-//ExtentOfTile return the Mercator extent of the given tile coords
+//ExtentOfTile return the Mercator extent of the given tile coords, the row is read according to the zoom level scheme
 func (z *ZoomLevel) ExtentOfTile(x, y int) ExtentM {
+	if !z.scheme.topLeft() {
+		y = FlipY(y, z.zoom)
+	}
+	return z.xyzExtentOfTile(x, y)
+}
+
+//xyzExtentOfTile return the Mercator extent of the given XYZ tile coords
+func (z *ZoomLevel) xyzExtentOfTile(x, y int) ExtentM {
 	minEast := (float64(x) * z.hLength) - (equator / 2)
 	maxEast := (float64(x+1) * z.hLength) - (equator / 2)
 	maxNorth := meridian - (float64(y) * z.vLength)
@@ -95,13 +127,14 @@ func (z *ZoomLevel) ExtentOfTile(x, y int) ExtentM {
 	return me
 }
 
-//RangeOf return the tile Range that covers the giveb EPSG:3857 extent
+//RangeOf return the tile Range that covers the giveb EPSG:3857 extent, rows are numbered according to the zoom level scheme.
+//Use RangesOf for extents crossing the antimeridian
 //https://developers.google.com/maps/documentation/javascript/coordinates
 func (z *ZoomLevel) RangeOf(ext ExtentM) Range {
-	tileUL := z.TileOfMerc(ext.UL())
-	tileLR := z.TileOfMerc(ext.LR())
+	tileUL := z.xyzTileOfMerc(ext.UL())
+	tileLR := z.xyzTileOfMerc(ext.LR())
 	r := Range{MinX: tileUL.X, MinY: tileUL.Y, MaxX: tileLR.X, MaxY: tileLR.Y, ZL: z.zoom}
-	return r
+	return ConvertRange(r, XYZ, z.scheme)
 }
 
 //RangeCardinality return the cardinality of the tile Range that covers the giveb EPSG:3857 extent
@@ -117,3 +150,20 @@ func ExtentOf(t Tile) ExtentM {
 	z := NewZoomLevel(t.Z)
 	return z.ExtentOfTile(t.X, t.Y)
 }
+
+//TileOfProjected gives the tile of the zoom level containing the given point of the projection
+func (z *ZoomLevel) TileOfProjected(p Projection, m PointM) (Tile, error) {
+	t, err := z.TileOfGeo(p.Inverse(m))
+	if err != nil {
+		return Tile{}, fmt.Errorf("EPSG:%s point (N, E)(%f, %f): %v", p.EPSG(), m.N, m.E, err)
+	}
+	return t, nil
+}
+
+//RangeOfProjected gives the tile Range of the zoom level covering the given extent of the projection
+func (z *ZoomLevel) RangeOfProjected(p Projection, ext ExtentM) Range {
+	ge := UnprojectExtent(p, ext)
+	ge.MaxLat = math.Min(ge.MaxLat, tileMaxLat)
+	ge.MinLat = math.Max(ge.MinLat, tileMinLat)
+	return z.RangeOf(GeoToMercExt(ge))
+}